@@ -0,0 +1,291 @@
+// Package catalog holds every scraped team JSON in memory, indexed by
+// league, season, team, nationality and player ID. A Watcher reloads it when
+// the files change and swaps the new catalog in atomically, so readers never
+// see a half-loaded index.
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"futbol912.com/countries"
+	"futbol912.com/leagues"
+	"futbol912.com/transfermarkt"
+)
+
+// Team is one team file of one league season.
+type Team struct {
+	League  string // league key, e.g. "premier"
+	Season  int
+	File    string // file name, e.g. "fc-arsenal.json"
+	Name    string // the file's "team" field, or the file name without extension
+	Players []transfermarkt.Player
+}
+
+// HasPositions reports whether any player of the team has a position. Files
+// scraped before positions were parsed have none, so position filters can't
+// work on them until the team is scraped again (or rebuilt with reparse).
+func (t *Team) HasPositions() bool {
+	for _, p := range t.Players {
+		if p.Position != "" || p.PositionGroup != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is a player together with the team file they were found in.
+type Entry struct {
+	transfermarkt.Player
+	Team *Team
+}
+
+type league struct {
+	info    leagues.League
+	seasons map[int]*season
+	latest  int
+}
+
+type season struct {
+	teams  []*Team // in file name order
+	byFile map[string]*Team
+}
+
+// Catalog is an immutable snapshot of the scraped data. Build a new one with
+// Load rather than modifying it.
+type Catalog struct {
+	order         []string // league keys in registry order
+	leagues       map[string]*league
+	byNationality map[string][]Entry
+	byID          map[string][]Entry
+	players       int
+}
+
+// Load reads every season directory of every league in the registry.
+// Players are normalized so the typed fields are always set.
+func Load(reg *leagues.Registry) (*Catalog, error) {
+	c := &Catalog{
+		leagues:       map[string]*league{},
+		byNationality: map[string][]Entry{},
+		byID:          map[string][]Entry{},
+	}
+	for _, l := range reg.Leagues {
+		lg := &league{info: l, seasons: map[int]*season{}}
+		c.order = append(c.order, l.Key)
+		c.leagues[l.Key] = lg
+
+		years, err := reg.Seasons(l)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, year := range years {
+			s, err := loadSeason(l.Key, year, reg.SeasonDir(l, year))
+			if err != nil {
+				return nil, err
+			}
+			lg.seasons[year] = s
+			lg.latest = year
+			for _, t := range s.teams {
+				c.index(t)
+			}
+		}
+	}
+	return c, nil
+}
+
+func loadSeason(key string, year int, dir string) (*season, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &season{byFile: map[string]*Team{}}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(strings.ToLower(name), ".json") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if !isTeamFile(b) {
+			continue
+		}
+		var tf struct {
+			Team    string                 `json:"team"`
+			Players []transfermarkt.Player `json:"players"`
+		}
+		if err := json.Unmarshal(b, &tf); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, name), err)
+		}
+		t := &Team{
+			League:  key,
+			Season:  year,
+			File:    name,
+			Name:    strings.TrimSpace(tf.Team),
+			Players: tf.Players,
+		}
+		if t.Name == "" {
+			t.Name = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		if t.Players == nil {
+			t.Players = []transfermarkt.Player{}
+		}
+		for i := range t.Players {
+			t.Players[i].Normalize()
+		}
+		s.teams = append(s.teams, t)
+		s.byFile[name] = t
+	}
+	return s, nil
+}
+
+// isTeamFile reports whether b looks like a team file: an object whose
+// "players" is a list. Other JSON left in a season directory, such as a
+// players_index.json from scrape -team, is skipped. Broken JSON counts as a
+// team file so that loading it reports the error.
+func isTeamFile(b []byte) bool {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(b, &top); err != nil {
+		var syntax *json.SyntaxError
+		return errors.As(err, &syntax)
+	}
+	players := bytes.TrimSpace(top["players"])
+	return len(players) > 0 && players[0] == '['
+}
+
+func (c *Catalog) index(t *Team) {
+	for _, p := range t.Players {
+		e := Entry{Player: p, Team: t}
+		c.players++
+		if p.ID != "" {
+			c.byID[p.ID] = append(c.byID[p.ID], e)
+		}
+		seen := map[string]bool{}
+		for _, n := range p.Nationalities {
+			k := nationalityKey(n)
+			if k != "" && !seen[k] {
+				seen[k] = true
+				c.byNationality[k] = append(c.byNationality[k], e)
+			}
+		}
+	}
+}
+
+func nationalityKey(name string) string {
+	return strings.ToLower(countries.Canonical(name))
+}
+
+// Leagues returns the registry entries in registry order.
+func (c *Catalog) Leagues() []leagues.League {
+	out := make([]leagues.League, 0, len(c.order))
+	for _, k := range c.order {
+		out = append(out, c.leagues[k].info)
+	}
+	return out
+}
+
+// League looks a league up by key.
+func (c *Catalog) League(key string) (leagues.League, bool) {
+	lg, ok := c.leagues[key]
+	if !ok {
+		return leagues.League{}, false
+	}
+	return lg.info, true
+}
+
+// Seasons lists the loaded seasons of a league, oldest first.
+func (c *Catalog) Seasons(key string) []int {
+	lg, ok := c.leagues[key]
+	if !ok {
+		return nil
+	}
+	out := make([]int, 0, len(lg.seasons))
+	for y := range lg.seasons {
+		out = append(out, y)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// LatestSeason is the most recent loaded season of a league, or 0.
+func (c *Catalog) LatestSeason(key string) int {
+	if lg, ok := c.leagues[key]; ok {
+		return lg.latest
+	}
+	return 0
+}
+
+// Teams returns a league season's teams in file name order. A zero season
+// means the latest one.
+func (c *Catalog) Teams(key string, year int) ([]*Team, bool) {
+	s, ok := c.season(key, year)
+	if !ok {
+		return nil, false
+	}
+	return s.teams, true
+}
+
+// Team looks a team up by file name, e.g. "fc-arsenal.json". A zero season
+// means the latest one.
+func (c *Catalog) Team(key string, year int, file string) (*Team, bool) {
+	s, ok := c.season(key, year)
+	if !ok {
+		return nil, false
+	}
+	t, ok := s.byFile[file]
+	return t, ok
+}
+
+func (c *Catalog) season(key string, year int) (*season, bool) {
+	lg, ok := c.leagues[key]
+	if !ok {
+		return nil, false
+	}
+	if year == 0 {
+		year = lg.latest
+	}
+	s, ok := lg.seasons[year]
+	return s, ok
+}
+
+// ByNationality returns every player entry with the given nationality,
+// accepting English or Spanish country names.
+func (c *Catalog) ByNationality(country string) []Entry {
+	return c.byNationality[nationalityKey(country)]
+}
+
+// Player returns every entry for a Transfermarkt player ID, one per team
+// season they appear in.
+func (c *Catalog) Player(id string) []Entry {
+	return c.byID[id]
+}
+
+// Entries returns every player entry of the given leagues' latest seasons,
+// or of all leagues when keys is empty, in league then team order.
+func (c *Catalog) Entries(keys ...string) []Entry {
+	if len(keys) == 0 {
+		keys = c.order
+	}
+	var out []Entry
+	for _, k := range keys {
+		teams, _ := c.Teams(k, 0)
+		for _, t := range teams {
+			for _, p := range t.Players {
+				out = append(out, Entry{Player: p, Team: t})
+			}
+		}
+	}
+	return out
+}
+
+// Size is the number of player entries across all seasons.
+func (c *Catalog) Size() int {
+	return c.players
+}
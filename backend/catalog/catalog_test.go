@@ -0,0 +1,51 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"futbol912.com/leagues"
+)
+
+func writeFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSkipsNonTeamFiles(t *testing.T) {
+	root := t.TempDir()
+	reg := &leagues.Registry{
+		Root:    root,
+		Leagues: []leagues.League{{Key: "premier", Code: "GB1", Season: 2025, Host: "h", Path: "p", Dir: "premier"}},
+	}
+	dir := reg.SeasonDir(reg.Leagues[0], 2025)
+	writeFile(t, filepath.Join(dir, "fc-arsenal.json"),
+		`{"team":"Arsenal FC","players":[{"id":"1","name":"Bukayo Saka"}]}`)
+	// scrape -team used to leave its index here; "players" is a map.
+	writeFile(t, filepath.Join(dir, "players_index.json"),
+		`{"players":{"1":{"id":"1","name":"Bukayo Saka"}}}`)
+	writeFile(t, filepath.Join(dir, "list.json"), `[1, 2, 3]`)
+
+	c, err := Load(reg)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	teams, ok := c.Teams("premier", 2025)
+	if !ok || len(teams) != 1 || teams[0].File != "fc-arsenal.json" {
+		t.Fatalf("teams = %v, want only fc-arsenal.json", teams)
+	}
+	if c.Size() != 1 {
+		t.Errorf("Size = %d, want 1", c.Size())
+	}
+
+	writeFile(t, filepath.Join(dir, "broken.json"), `{"team":"Broken","players":[`)
+	if _, err := Load(reg); err == nil {
+		t.Error("Load with a broken team file: want an error")
+	}
+}
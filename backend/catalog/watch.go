@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"futbol912.com/leagues"
+)
+
+// Watcher serves the current Catalog and reloads it when the team files
+// change. Changes are detected by polling file count, sizes and mtimes.
+type Watcher struct {
+	reg *leagues.Registry
+	cur atomic.Pointer[Catalog]
+
+	mu    sync.Mutex // serializes reloads
+	stamp string
+}
+
+// NewWatcher loads the catalog once. Call Run to keep it up to date.
+func NewWatcher(reg *leagues.Registry) (*Watcher, error) {
+	w := &Watcher{reg: reg}
+	stamp, err := w.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	c, err := Load(reg)
+	if err != nil {
+		return nil, err
+	}
+	w.cur.Store(c)
+	w.stamp = stamp
+	return w, nil
+}
+
+// Catalog returns the current snapshot. It is safe for concurrent use; hold
+// on to the returned value for the duration of a request.
+func (w *Watcher) Catalog() *Catalog {
+	return w.cur.Load()
+}
+
+// Run polls every interval until ctx is done. A reload that fails keeps
+// serving the previous catalog.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := w.Reload(false); err != nil {
+				log.Printf("catalog: reload failed, keeping previous data: %v", err)
+			}
+		}
+	}
+}
+
+// Reload rebuilds the catalog if the files changed since the last load, or
+// unconditionally when force is set.
+func (w *Watcher) Reload(force bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stamp, err := w.fingerprint()
+	if err != nil {
+		return err
+	}
+	if !force && stamp == w.stamp {
+		return nil
+	}
+	c, err := Load(w.reg)
+	if err != nil {
+		return err
+	}
+	w.cur.Store(c)
+	w.stamp = stamp
+	log.Printf("catalog: reloaded %d players", c.Size())
+	return nil
+}
+
+func (w *Watcher) fingerprint() (string, error) {
+	h := sha256.New()
+	for _, l := range w.reg.Leagues {
+		root := w.reg.DataDir(l)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root && errors.Is(err, fs.ErrNotExist) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(strings.ToLower(d.Name()), ".json") {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s %d %d\n", path, info.ModTime().UnixNano(), info.Size())
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"futbol912.com/catalog"
+	"futbol912.com/games/bingo"
+	"github.com/gin-gonic/gin"
+)
+
+// boardLoader resolves the board addressed by the request, writing an error
+// response and returning false when it cannot.
+type boardLoader func(c *gin.Context) (bingo.Board, bool)
+
+// registerBingoRoutes exposes the boards normalized by the games/bingo
+// package, plus boards generated from our own scraped rosters and from the
+// box2box game, when it was loaded.
+func registerBingoRoutes(r *gin.Engine, store *bingo.Store, roster func() []bingo.RosterPlayer, box2box *bingo.Box2Box) {
+	r.GET("/api/bingo", func(c *gin.Context) {
+		ids, err := store.IDs()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "could not list bingo boards",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": len(ids), "boards": ids})
+	})
+
+	remote := func(c *gin.Context) (bingo.Board, bool) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board id"})
+			return bingo.Board{}, false
+		}
+		board, err := store.Board(c.Request.Context(), id)
+		if err != nil {
+			bingoError(c, err)
+			return bingo.Board{}, false
+		}
+		return board, true
+	}
+
+	boards := &generatedBoards{boards: map[int64]bingo.Board{}}
+	generated := func(c *gin.Context) (bingo.Board, bool) {
+		seed, err := strconv.ParseInt(c.Param("seed"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid seed"})
+			return bingo.Board{}, false
+		}
+		board, err := boards.get(seed, func() (bingo.Board, error) {
+			return bingo.Generate(roster(), bingo.GenerateOptions{Seed: seed})
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "could not generate bingo board",
+				"message": err.Error(),
+			})
+			return bingo.Board{}, false
+		}
+		return board, true
+	}
+
+	box2boxBoards := &generatedBoards{boards: map[int64]bingo.Board{}}
+	fromBox2Box := func(c *gin.Context) (bingo.Board, bool) {
+		if box2box == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "box2box game not available"})
+			return bingo.Board{}, false
+		}
+		seed, err := strconv.ParseInt(c.Param("seed"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid seed"})
+			return bingo.Board{}, false
+		}
+		board, err := box2boxBoards.get(seed, func() (bingo.Board, error) {
+			return box2box.Generate(bingo.GenerateOptions{Seed: seed})
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "could not generate bingo board",
+				"message": err.Error(),
+			})
+			return bingo.Board{}, false
+		}
+		return board, true
+	}
+
+	r.GET("/api/bingo/:id", boardHandler(remote))
+	r.POST("/api/bingo/:id/check", checkHandler(remote))
+	r.GET("/api/bingo/generated/:seed", boardHandler(generated))
+	r.POST("/api/bingo/generated/:seed/check", checkHandler(generated))
+	r.GET("/api/bingo/box2box/:seed", boardHandler(fromBox2Box))
+	r.POST("/api/bingo/box2box/:seed/check", checkHandler(fromBox2Box))
+}
+
+// maxGeneratedBoards bounds the generated boards kept in memory; the oldest
+// seed is dropped first and rebuilt from the current roster if asked again.
+const maxGeneratedBoards = 512
+
+// generatedBoards keeps the board served for each seed, so /check validates
+// against the board the player is looking at even after the catalog
+// reloads with different rosters.
+type generatedBoards struct {
+	mu     sync.Mutex
+	boards map[int64]bingo.Board
+	order  []int64 // seeds, oldest first
+}
+
+// get returns the board kept for seed, building and keeping it on a miss.
+func (g *generatedBoards) get(seed int64, build func() (bingo.Board, error)) (bingo.Board, error) {
+	g.mu.Lock()
+	board, ok := g.boards[seed]
+	g.mu.Unlock()
+	if ok {
+		return board, nil
+	}
+
+	board, err := build()
+	if err != nil {
+		return bingo.Board{}, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// Another request may have built it meanwhile; keep the first one.
+	if kept, ok := g.boards[seed]; ok {
+		return kept, nil
+	}
+	if len(g.order) >= maxGeneratedBoards {
+		delete(g.boards, g.order[0])
+		g.order = g.order[1:]
+	}
+	g.boards[seed] = board
+	g.order = append(g.order, seed)
+	return board, nil
+}
+
+// catalogRoster returns the latest season of every league as bingo roster
+// players, in registry and team order so a seed keeps producing the same
+// board until the data changes.
+func catalogRoster(w *catalog.Watcher) func() []bingo.RosterPlayer {
+	return func() []bingo.RosterPlayer {
+		cat := w.Catalog()
+		var out []bingo.RosterPlayer
+		for _, l := range cat.Leagues() {
+			teams, _ := cat.Teams(l.Key, 0)
+			src := bingo.RosterSource{League: l.Key, Name: l.Name}
+			for _, t := range teams {
+				out = append(out, bingo.TeamRoster(src, t.Name, t.File, t.Players)...)
+			}
+		}
+		return out
+	}
+}
+
+func boardHandler(load boardLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		board, ok := load(c)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, publicBoard(board))
+	}
+}
+
+// publicBoard is the board as sent to clients. Only names go out;
+// placements are validated by /check.
+func publicBoard(board bingo.Board) gin.H {
+	public := make([]bingoPlayerView, 0, len(board.Players))
+	for _, p := range board.Players {
+		public = append(public, bingoPlayerView{ID: p.ID, Name: p.Name})
+	}
+	return gin.H{
+		"id":         board.ID,
+		"cells":      board.Cells,
+		"categories": board.Categories,
+		"players":    public,
+	}
+}
+
+func checkHandler(load boardLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req bingoCheckRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		board, ok := load(c)
+		if !ok {
+			return
+		}
+
+		onBoard := false
+		for _, cat := range board.Categories {
+			if cat.ID == req.CategoryID {
+				onBoard = true
+				break
+			}
+		}
+		if !onBoard {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found on board"})
+			return
+		}
+
+		var player *bingo.Player
+		for i := range board.Players {
+			if board.Players[i].ID == req.PlayerID {
+				player = &board.Players[i]
+				break
+			}
+		}
+		if player == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "player not found on board"})
+			return
+		}
+
+		// Every board category the player would satisfy, in board order.
+		matches := []int{}
+		seen := map[int]bool{}
+		for _, cat := range board.Categories {
+			if !seen[cat.ID] && player.Satisfies(cat.ID) {
+				matches = append(matches, cat.ID)
+			}
+			seen[cat.ID] = true
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"playerId":   player.ID,
+			"categoryId": req.CategoryID,
+			"correct":    player.Satisfies(req.CategoryID),
+			"categories": matches,
+		})
+	}
+}
+
+// bingoPlayerView is a board player without the categories they satisfy.
+type bingoPlayerView struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type bingoCheckRequest struct {
+	PlayerID   int `json:"playerId" binding:"required"`
+	CategoryID int `json:"categoryId" binding:"required"`
+}
+
+func bingoError(c *gin.Context, err error) {
+	if errors.Is(err, bingo.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bingo board not found"})
+		return
+	}
+	c.JSON(http.StatusBadGateway, gin.H{
+		"error":   "could not load bingo board",
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sort"
+
+	"futbol912.com/catalog"
+	"futbol912.com/daily"
+	"futbol912.com/games/bingo"
+	"futbol912.com/questions"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	dailyQuestions = 5
+	dailyPlayers   = 10
+)
+
+// dailySnapshot is what gets stored per date. Players and the board are
+// stored whole so a past daily survives roster changes; questions are
+// stored by ID because their answers stay server-side. Players are served
+// without their ages, which answer the age rounds; see dailyAgeCheck.
+type dailySnapshot struct {
+	Date      string       `json:"date"`
+	Questions []string     `json:"questions"`
+	Players   []playerView `json:"players"`
+	Bingo     bingo.Board  `json:"bingo"`
+}
+
+type dailyDeps struct {
+	secret    []byte
+	store     *daily.Store
+	questions []questions.Question
+	sessions  *questions.Sessions
+	players   *catalog.Watcher
+	roster    func() []bingo.RosterPlayer
+}
+
+// registerDailyRoutes serves the daily challenge: the same quiz questions,
+// age-quiz players and bingo board for everyone on a UTC date.
+func registerDailyRoutes(r *gin.Engine, d dailyDeps) {
+	byID := map[string]questions.Question{}
+	for _, q := range d.questions {
+		byID[q.ID] = q
+	}
+
+	load := func(c *gin.Context, date string) (dailySnapshot, bool) {
+		date, err := daily.ParseDate(date)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, daily.ErrFuture) {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return dailySnapshot{}, false
+		}
+		// Only today's daily is built from the current data; a past one is
+		// whatever was served that day, or nothing.
+		var snap dailySnapshot
+		if date == daily.Today() {
+			err = d.store.GetOrCreate(date, &snap, func() (any, error) {
+				return d.build(date)
+			})
+		} else {
+			err = d.store.Get(date, &snap)
+		}
+		if errors.Is(err, daily.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return dailySnapshot{}, false
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not build daily", "message": err.Error()})
+			return dailySnapshot{}, false
+		}
+		return snap, true
+	}
+
+	serve := func(c *gin.Context, date string) {
+		snap, ok := load(c, date)
+		if !ok {
+			return
+		}
+		var qs []questions.Question
+		views := []quizQuestionView{}
+		for _, id := range snap.Questions {
+			if q, ok := byID[id]; ok {
+				qs = append(qs, q)
+				views = append(views, newQuizQuestionView(q))
+			}
+		}
+		session, err := d.sessions.StartSet(dailyQuizSet(snap.Date), qs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not start quiz session", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"date":      snap.Date,
+			"session":   session,
+			"questions": views,
+			"players":   dailyPlayerViews(snap.Players),
+			"bingo":     publicBoard(snap.Bingo),
+		})
+	}
+
+	r.GET("/api/daily", func(c *gin.Context) {
+		serve(c, daily.Today())
+	})
+	r.GET("/api/daily/:date", func(c *gin.Context) {
+		serve(c, c.Param("date"))
+	})
+	r.POST("/api/daily/:date/bingo/check", checkHandler(func(c *gin.Context) (bingo.Board, bool) {
+		snap, ok := load(c, c.Param("date"))
+		return snap.Bingo, ok
+	}))
+	r.POST("/api/daily/:date/age/check", func(c *gin.Context) {
+		var req dailyAgeCheck
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "message": err.Error()})
+			return
+		}
+		snap, ok := load(c, c.Param("date"))
+		if !ok {
+			return
+		}
+		for _, p := range snap.Players {
+			if p.ID == req.PlayerID {
+				c.JSON(http.StatusOK, gin.H{
+					"player_id": p.ID,
+					"correct":   *req.Age == p.AgeYears,
+					"age_years": p.AgeYears,
+				})
+				return
+			}
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not in this daily", "player_id": req.PlayerID})
+	})
+}
+
+// dailyAgeCheck is a guess at the age of one of a daily's players. The age
+// is revealed with the answer, as the quiz reveals answers on a guess.
+type dailyAgeCheck struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Age      *int   `json:"age" binding:"required"`
+}
+
+// dailyPlayerViews is the served form of a daily's players: the session view,
+// which leaves out age_years and birthdate.
+func dailyPlayerViews(stored []playerView) []sessionPlayer {
+	out := make([]sessionPlayer, 0, len(stored))
+	for _, p := range stored {
+		out = append(out, sessionPlayer{
+			ID:       p.ID,
+			Name:     p.Name,
+			Team:     p.Team,
+			League:   p.League,
+			Position: p.Position,
+			PhotoURL: p.PhotoURL,
+		})
+	}
+	return out
+}
+
+// dailyQuizSet names the quiz session set of a day's questions; only
+// today's is ranked.
+func dailyQuizSet(date string) string {
+	return "daily/" + date
+}
+
+// build picks a day's challenge from the current data. Inputs are put in a
+// fixed order first so the picks depend only on the data and the seed.
+func (d dailyDeps) build(date string) (dailySnapshot, error) {
+	snap := dailySnapshot{Date: date, Questions: []string{}, Players: []playerView{}}
+
+	ids := make([]string, 0, len(d.questions))
+	for _, q := range d.questions {
+		ids = append(ids, q.ID)
+	}
+	sort.Strings(ids)
+	rng := rand.New(rand.NewSource(daily.Seed(d.secret, date, "questions")))
+	rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	snap.Questions = append(snap.Questions, ids[:min(dailyQuestions, len(ids))]...)
+
+	var pool []catalog.Entry
+	for _, e := range d.players.Catalog().Entries() {
+		if e.Name != "" && e.AgeYears > 0 {
+			pool = append(pool, e)
+		}
+	}
+	rng = rand.New(rand.NewSource(daily.Seed(d.secret, date, "players")))
+	n := min(dailyPlayers, len(pool))
+	for i := 0; i < n; i++ {
+		j := i + rng.Intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+		snap.Players = append(snap.Players, newPlayerView(pool[i]))
+	}
+
+	board, err := bingo.Generate(d.roster(), bingo.GenerateOptions{Seed: daily.Seed(d.secret, date, "bingo")})
+	if err != nil {
+		return dailySnapshot{}, err
+	}
+	snap.Bingo = board
+	return snap, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"futbol912.com/daily"
+	"futbol912.com/leaderboard"
+	"futbol912.com/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLeaderboardLimit = 20
+	maxLeaderboardLimit     = 100
+)
+
+// leaderboardModes are the modes with boards: the list quiz plus every
+// session mode.
+var leaderboardModes = append([]sessions.Mode{sessions.ModeQuestions}, sessions.Modes...)
+
+// leaderboardEntry is a ranked entry without the player's token.
+type leaderboardEntry struct {
+	Rank  int       `json:"rank"`
+	Name  string    `json:"name"`
+	Score int       `json:"score"`
+	At    time.Time `json:"at"`
+}
+
+func newLeaderboardEntry(r leaderboard.Ranked) leaderboardEntry {
+	return leaderboardEntry{Rank: r.Rank, Name: r.Name, Score: r.Score, At: r.At}
+}
+
+type leaderboardSubmitRequest struct {
+	Result    sessions.Result `json:"result"`
+	Signature string          `json:"signature" binding:"required"`
+	Player    string          `json:"player" binding:"required"`
+	Name      string          `json:"name"`
+}
+
+// registerLeaderboardRoutes serves the per-mode boards. Scores come in as
+// the signed results of /api/sessions/:id/finish or /api/quiz/finish, so a
+// client can't post a score it didn't play.
+func registerLeaderboardRoutes(r *gin.Engine, boards *leaderboard.Leaderboards, games *sessions.Manager) {
+	r.GET("/api/leaderboards/:mode", func(c *gin.Context) {
+		mode, ok := leaderboardMode(c)
+		if !ok {
+			return
+		}
+		period, err := leaderboard.ParsePeriod(c.Query("period"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// ?date= elige el día o la semana (la que contiene esa fecha);
+		// por defecto hoy
+		at := time.Now()
+		if v := c.Query("date"); v != "" {
+			at, err = time.Parse(daily.DateLayout, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": daily.ErrInvalidDate.Error()})
+				return
+			}
+		}
+
+		page, limit := 1, defaultLeaderboardLimit
+		if v := c.Query("page"); v != "" {
+			if page, err = strconv.Atoi(v); err != nil || page <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page"})
+				return
+			}
+		}
+		if v := c.Query("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			limit = min(limit, maxLeaderboardLimit)
+		}
+
+		key := leaderboard.Key(period, at)
+		board := leaderboard.Board(string(mode), period, key)
+		ranked, total, err := boards.Page(board, (page-1)*limit, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not read leaderboard", "message": err.Error()})
+			return
+		}
+		entries := make([]leaderboardEntry, 0, len(ranked))
+		for _, e := range ranked {
+			entries = append(entries, newLeaderboardEntry(e))
+		}
+		resp := gin.H{
+			"mode":    mode,
+			"period":  period,
+			"key":     key,
+			"total":   total,
+			"page":    page,
+			"limit":   limit,
+			"entries": entries,
+		}
+
+		// ?player=<token> agrega la posición de ese jugador ("me"), null
+		// si no figura en este ranking
+		if player := c.Query("player"); player != "" {
+			if !leaderboard.ValidPlayer(player) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": leaderboard.ErrInvalidPlayer.Error()})
+				return
+			}
+			me, found, err := boards.Rank(board, player)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not read leaderboard", "message": err.Error()})
+				return
+			}
+			resp["me"] = nil
+			if found {
+				resp["me"] = newLeaderboardEntry(me)
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+
+	r.POST("/api/leaderboards/:mode", func(c *gin.Context) {
+		mode, ok := leaderboardMode(c)
+		if !ok {
+			return
+		}
+		var req leaderboardSubmitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "message": err.Error()})
+			return
+		}
+		if !games.Verify(req.Result, req.Signature) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid result signature"})
+			return
+		}
+		if req.Result.Mode != mode {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "result is for mode " + string(req.Result.Mode)})
+			return
+		}
+		if req.Result.FinishedAt.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "game not finished"})
+			return
+		}
+		if req.Result.Rounds != rankedRounds(mode) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ranked %s games have %d rounds", mode, rankedRounds(mode))})
+			return
+		}
+
+		ranks, err := boards.Submit(string(mode), leaderboard.Entry{
+			Player:  req.Player,
+			Name:    req.Name,
+			Score:   req.Result.Score,
+			Session: req.Result.Session,
+			At:      req.Result.FinishedAt,
+		})
+		switch {
+		case errors.Is(err, leaderboard.ErrInvalidPlayer), errors.Is(err, leaderboard.ErrTooOld):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, leaderboard.ErrAlreadySubmitted):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save score", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"mode": mode, "score": req.Result.Score, "ranks": ranks})
+	})
+}
+
+func leaderboardMode(c *gin.Context) (sessions.Mode, bool) {
+	for _, m := range leaderboardModes {
+		if string(m) == c.Param("mode") {
+			return m, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "unknown game mode", "modes": leaderboardModes})
+	return "", false
+}
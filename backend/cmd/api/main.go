@@ -4,20 +4,46 @@ package main
 //
 // Rutas disponibles:
 // - GET /                              - Health check y información de la API
-// - GET /api/list/:league              - Lista equipos de una liga (premier, laliga, bundesliga, seriea, ligue1)
-// - GET /api/get/:league/:team         - Obtiene jugadores de un equipo específico
-// - GET /api/quiz/questions            - Obtiene preguntas de quiz (parámetro opcional: ?count=N)
+// - GET /api/list/:league              - Lista equipos de una liga (claves definidas en leagues.json; ?season=2024 opcional)
+// - GET /api/get/:league/:team         - Obtiene jugadores de un equipo específico (?season=2024 opcional, por defecto la más reciente)
+// - GET /api/players/random           - Muestra aleatoria de jugadores (?count, league, team, nationality, min_age, max_age, has_photo, has_market_value, seed)
+// - GET /api/players/search?q=         - Busca jugadores por nombre, sin acentos y tolerando errores de tipeo
+// - GET /api/quiz/questions            - Obtiene preguntas de quiz sin respuestas, y abre una sesión (?count=N, ?tag=club:arsenal, ?difficulty=easy)
+// - GET /api/quiz/questions/:id        - Obtiene una pregunta por su id estable (sin respuestas)
+// - POST /api/quiz/questions/:id/guess - Comprueba un intento ({"session", "guess"}) y devuelve la respuesta revelada
+// - POST /api/quiz/questions/:id/giveup - Termina la pregunta y devuelve todas las respuestas
+// - GET /api/daily                     - Desafío del día (UTC): preguntas, jugadores para el quiz de edades y un tablero de bingo
+// - GET /api/daily/:date               - Desafío guardado de un día anterior (YYYY-MM-DD; 404 si no se jugó)
+// - POST /api/daily/:date/bingo/check  - Valida una colocación en el bingo del día
+// - POST /api/daily/:date/age/check    - Comprueba una edad del quiz del día ({"player_id", "age"}); los jugadores se sirven sin edad
+// - POST /api/sessions                 - Empieza una partida puntuada ({"mode": "age|team|nationality|bingo"}; 10 rondas, 42 jugadores en bingo)
+// - GET /api/sessions/:id              - Estado de la partida: puntaje, racha y la ronda a responder
+// - POST /api/sessions/:id/answers     - Responde la ronda actual ({"skip": true} pasa en bingo); el servidor calcula puntos, racha y bonus por tiempo
+// - POST /api/sessions/:id/finish      - Termina la partida y devuelve el resultado firmado
+// - POST /api/quiz/finish              - Cierra una sesión del quiz; el resultado se firma solo para las preguntas de hoy del desafío diario
+// - GET /api/leaderboards/:mode        - Ranking de un modo (questions, age, team, nationality, bingo; ?period=global|daily|weekly, ?date, ?page, ?limit, ?player=token)
+// - POST /api/leaderboards/:mode       - Sube un resultado firmado ({"result", "signature", "player", "name"}); hasta 7 días después de la partida
+// - GET /api/bingo                     - Lista los IDs de tableros de bingo disponibles
+// - GET /api/bingo/:id                 - Obtiene un tablero de bingo normalizado (sin respuestas)
+// - POST /api/bingo/:id/check          - Valida si un jugador encaja en una categoría del tablero
+// - GET /api/bingo/generated/:seed     - Genera un tablero propio a partir de los planteles scrapeados
+// - POST /api/bingo/generated/:seed/check - Valida una colocación en un tablero generado
+// - GET /api/bingo/box2box/:seed       - Genera un tablero con las reglas y jugadores de box2box
+// - POST /api/bingo/box2box/:seed/check - Valida una colocación en un tablero box2box
 //
 // Ejemplo de uso:
 // - GET /api/list/premier              - Lista equipos de Premier League
 // - GET /api/get/premier/arsenal       - Obtiene jugadores del Arsenal
+// - GET /api/get/premier/arsenal.json?season=2022 - Plantel del Arsenal en la temporada 2022/23
+// - GET /api/get/premier/arsenal.json?group=DEF   - Solo los defensores (GK, DEF, MID, FWD; o ?position=Centre-Back).
+//   Responde 409 si el JSON del equipo es anterior al scrapeo de posiciones: hay que volver a scrapearlo.
 // - GET /api/quiz/questions?count=10   - Obtiene 10 preguntas de quiz aleatorias
+// - GET /api/bingo/720                 - Obtiene el tablero de bingo 720
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
-	"math/rand"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -27,25 +53,18 @@ import (
 	"strings"
 	"time"
 
+	"futbol912.com/catalog"
+	"futbol912.com/daily"
+	"futbol912.com/games/bingo"
+	"futbol912.com/leaderboard"
+	"futbol912.com/leagues"
+	"futbol912.com/questions"
+	"futbol912.com/sessions"
+	"futbol912.com/transfermarkt"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
-type QuizQuestion struct {
-	GameData struct {
-		Question string   `json:"question"`
-		Answers  []string `json:"answers"`
-	} `json:"gameData"`
-}
-
-type QuizQuestionsResponse struct {
-	Questions []QuizQuestion `json:"questions"`
-}
-
-type QuizGameRequest struct {
-	Count int `json:"count"` // Número de preguntas que quiere el cliente
-}
-
 func main() {
 	err := godotenv.Load("../../.env")
 	if err != nil {
@@ -65,17 +84,25 @@ func main() {
 
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", corsOrigin)
-		c.Header("Access-Control-Allow-Methods", "GET")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Content-Type")
 
+		// Responder preflight de CORS para los endpoints POST
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
 		c.Next()
 	})
 
-	findDataDir := func(subdir string) string {
+	// findDataDir busca un directorio relativo a backend/ desde donde se
+	// ejecute el binario (backend/, cmd/ o cmd/api/)
+	findDataDir := func(rel string) string {
 		candidates := []string{
-			filepath.Join("cmd", subdir),
-			filepath.Join("..", "cmd", subdir),
-			filepath.Join("..", "..", "cmd", subdir),
+			rel,
+			filepath.Join("..", rel),
+			filepath.Join("..", "..", rel),
 		}
 		for _, p := range candidates {
 			if info, err := os.Stat(p); err == nil && info.IsDir() {
@@ -83,16 +110,57 @@ func main() {
 				return p
 			}
 		}
-		fallback := filepath.Join("cmd", subdir)
-		fmt.Printf("Warning: data dir not found, using fallback: %s\n", fallback)
-		return fallback
+		fmt.Printf("Warning: data dir not found, using fallback: %s\n", rel)
+		return rel
+	}
+
+	registry, err := leagues.LoadDefault()
+	if err != nil {
+		log.Fatalf("could not load league registry: %v", err)
+	}
+	// Todos los planteles quedan en memoria; se recargan solos cuando
+	// cambian los archivos (CATALOG_POLL, por defecto cada 30s)
+	players, err := catalog.NewWatcher(registry)
+	if err != nil {
+		log.Fatalf("could not load player catalog: %v", err)
+	}
+	fmt.Printf("Catalog loaded: %d players\n", players.Catalog().Size())
+	poll := 30 * time.Second
+	if v := os.Getenv("CATALOG_POLL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			poll = d
+		}
 	}
-	leagues := map[string]string{
-		"laligaes":   findDataDir("scrape_laliga"),
-		"premier":    findDataDir("scrape_premier"),
-		"seriea":     findDataDir("scrape_seriea"),
-		"ligue1":     findDataDir("scrape_ligue1"),
-		"bundesliga": findDataDir("scrape_bundesliga"),
+	go players.Run(context.Background(), poll)
+
+	leagueNames := gin.H{}
+	for _, l := range registry.Leagues {
+		leagueNames[l.Key] = fmt.Sprintf("%s (%s)", l.Name, l.Country)
+	}
+
+	// leagueSeason resuelve :league y ?season= (por defecto la temporada
+	// más reciente) contra el catálogo. Escribe el error y devuelve false
+	// si no puede.
+	leagueSeason := func(c *gin.Context, cat *catalog.Catalog) (string, int, bool) {
+		key := c.Param("league")
+		if _, ok := cat.League(key); !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "league not found"})
+			return "", 0, false
+		}
+		season := cat.LatestSeason(key)
+		if q := c.Query("season"); q != "" {
+			n, err := strconv.Atoi(q)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season"})
+				return "", 0, false
+			}
+			season = n
+		}
+		if _, ok := cat.Teams(key, season); !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "season not found", "season": season, "seasons": cat.Seasons(key)})
+			return "", 0, false
+		}
+		return key, season, true
 	}
 
 	validTeam := regexp.MustCompile(`^[A-Za-z0-9._\-]+\.json$`)
@@ -107,20 +175,81 @@ func main() {
 				"teams": gin.H{
 					"url":         "/api/get/{league}/{team}.json",
 					"description": "Obtener jugadores de un equipo específico",
+					"params":      "?season=2025 (opcional, por defecto la temporada más reciente), ?group=GK|DEF|MID|FWD, ?position=Centre-Back",
+				},
+				"players_random": gin.H{
+					"url":         "/api/players/random",
+					"description": "Jugadores al azar de todos los planteles (mismo seed, misma muestra)",
+					"params":      "?count=20&league=premier,laligaes&team=fc-arsenal&nationality=Argentina&min_age=18&max_age=23&has_photo=1&has_market_value=1&seed=42",
+				},
+				"players_search": gin.H{
+					"url":         "/api/players/search?q={nombre}",
+					"description": "Buscar jugadores (exactos, prefijos y con errores de tipeo; mismos filtros que random)",
+					"params":      "?q=mbape&limit=20",
 				},
 				"quiz": gin.H{
 					"url":         "/api/quiz/questions",
-					"description": "Obtener preguntas para el quiz",
-					"params":      "?count=10 (opcional, por defecto todas)",
+					"description": "Obtener preguntas para el quiz (solo texto y cantidad de respuestas, más un id de sesión)",
+					"params":      "?count=10 (opcional, por defecto todas), ?tag=league:premier-league,era:2010s, ?difficulty=easy|medium|hard",
+				},
+				"quiz_guess": gin.H{
+					"url":         "POST /api/quiz/questions/{id}/guess",
+					"description": "Comprobar un intento; tolera acentos, mayúsculas, solo apellido y errores de tipeo",
+					"body":        `{"session": "...", "guess": "van persie"}`,
+				},
+				"quiz_giveup": gin.H{
+					"url":         "POST /api/quiz/questions/{id}/giveup",
+					"description": "Rendirse: devuelve todas las respuestas de la pregunta",
+					"body":        `{"session": "..."}`,
+				},
+				"daily": gin.H{
+					"url":         "/api/daily",
+					"description": "Desafío del día, igual para todos; /api/daily/{YYYY-MM-DD} para días anteriores",
+				},
+				"daily_age_check": gin.H{
+					"url":         "POST /api/daily/{YYYY-MM-DD}/age/check",
+					"description": "Comprueba la edad de un jugador del día y la revela",
+					"body":        `{"player_id": "...", "age": 27}`,
+				},
+				"sessions": gin.H{
+					"url":         "POST /api/sessions",
+					"description": "Partida puntuada en el servidor; responder con POST /api/sessions/{id}/answers y cerrar con /finish",
+					"body":        `{"mode": "age"}`,
+				},
+				"sessions_answer": gin.H{
+					"url":         "POST /api/sessions/{id}/answers",
+					"description": "Responder la ronda actual (age, choice, players o cell según el modo; en bingo skip pasa al siguiente jugador sin usar casilla)",
+					"body":        `{"round": 0, "age": 27}`,
+				},
+				"leaderboards": gin.H{
+					"url":         "/api/leaderboards/{mode}",
+					"description": "Ranking global, diario o semanal por modo (questions, age, team, nationality, bingo); ?player= agrega tu posición",
+					"params":      "?period=weekly&page=1&limit=20&player=...",
+				},
+				"leaderboards_submit": gin.H{
+					"url":         "POST /api/leaderboards/{mode}",
+					"description": "Subir el resultado firmado de /api/sessions/{id}/finish o /api/quiz/finish",
+					"body":        `{"result": {...}, "signature": "...", "player": "token-anonimo", "name": "Diego"}`,
+				},
+				"bingo": gin.H{
+					"url":         "/api/bingo/{id}",
+					"description": "Obtener un tablero de bingo (GET /api/bingo lista los IDs disponibles)",
+				},
+				"bingo_check": gin.H{
+					"url":         "POST /api/bingo/{id}/check",
+					"description": "Validar la colocación de un jugador en una celda",
+					"body":        `{"playerId": 1084, "categoryId": 83}`,
+				},
+				"bingo_generated": gin.H{
+					"url":         "/api/bingo/generated/{seed}",
+					"description": "Tablero generado con nuestros planteles (mismo seed, mismo tablero)",
+				},
+				"bingo_box2box": gin.H{
+					"url":         "/api/bingo/box2box/{seed}",
+					"description": "Tablero generado con las categorías y jugadores de box2box (mismo seed, mismo tablero)",
 				},
 			},
-			"leagues": gin.H{
-				"premier":    "Premier League (Inglaterra)",
-				"laligaes":   "La Liga (España)",
-				"bundesliga": "Bundesliga (Alemania)",
-				"seriea":     "Serie A (Italia)",
-				"ligue1":     "Ligue 1 (Francia)",
-			},
+			"leagues": leagueNames,
 			"examples": []string{
 				"/api/get/premier/manchester-city.json",
 				"/api/get/laligaes/real-madrid.json",
@@ -131,16 +260,9 @@ func main() {
 	})
 
 	r.GET("/api/list/:league", func(c *gin.Context) {
-		league := c.Param("league")
-		dir, ok := leagues[league]
+		cat := players.Catalog()
+		league, season, ok := leagueSeason(c, cat)
 		if !ok {
-			c.JSON(http.StatusNotFound, gin.H{"error": "league not found"})
-			return
-		}
-
-		entries, err := os.ReadDir(dir)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read data directory", "detail": err.Error()})
 			return
 		}
 
@@ -149,139 +271,146 @@ func main() {
 			Team string `json:"team"`
 		}
 
-		var teams []TeamInfo
-		for _, e := range entries {
-			if e.IsDir() {
-				continue
-			}
-			name := e.Name()
-			if !strings.HasSuffix(strings.ToLower(name), ".json") {
-				continue
-			}
-
-			teamName := strings.TrimSuffix(name, ".json")
-			path := filepath.Join(dir, name)
-			if b, err := os.ReadFile(path); err == nil {
-				var tmp map[string]interface{}
-				if err := json.Unmarshal(b, &tmp); err == nil {
-					if t, ok := tmp["team"].(string); ok && strings.TrimSpace(t) != "" {
-						teamName = t
-					}
-				}
-			}
-
-			teams = append(teams, TeamInfo{File: name, Team: teamName})
+		all, _ := cat.Teams(league, season)
+		teams := make([]TeamInfo, 0, len(all))
+		for _, t := range all {
+			teams = append(teams, TeamInfo{File: t.File, Team: t.Name})
 		}
 
 		sort.Slice(teams, func(i, j int) bool { return teams[i].Team < teams[j].Team })
 
-		c.JSON(http.StatusOK, gin.H{"league": league, "teams": teams})
+		c.JSON(http.StatusOK, gin.H{"league": league, "season": season, "teams": teams})
 	})
 
 	r.GET("/api/get/:league/:team", func(c *gin.Context) {
-		league := c.Param("league")
-		team := c.Param("team")
-
-		dir, ok := leagues[league]
+		cat := players.Catalog()
+		league, season, ok := leagueSeason(c, cat)
 		if !ok {
-			c.JSON(http.StatusNotFound, gin.H{"error": "league not found"})
 			return
 		}
-		if !validTeam.MatchString(team) {
+		team := c.Param("team")
+		if !validTeam.MatchString(team) || strings.Contains(team, "..") {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team name"})
 			return
 		}
 
-		filePath := filepath.Join(dir, team)
-
-		fmt.Printf("League: %s, Team: %s\n", league, team)
-		fmt.Printf("Dir: %s, FilePath: %s\n", dir, filePath)
-
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			fmt.Printf("File not found: %s\n", filePath)
-			c.JSON(http.StatusNotFound, gin.H{"error": "team json not found", "path": filePath})
+		t, ok := cat.Team(league, season, team)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "team json not found", "team": team, "season": season})
 			return
 		}
 
-		if strings.Contains(team, "..") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team name"})
+		// Filtrar por posición (?position=Centre-Back) o grupo (?group=DEF)
+		position := strings.TrimSpace(c.Query("position"))
+		group := strings.TrimSpace(c.Query("group"))
+		if (position != "" || group != "") && !t.HasPositions() {
+			// Los JSON anteriores al scrapeo de posiciones no las tienen
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "positions not scraped for this team",
+				"message": "re-scrape the team (scrape, or reparse from a snapshot archive) to filter by position",
+				"team":    team,
+				"season":  season,
+			})
 			return
 		}
-
-		c.File(filePath)
-	})
-
-	// Endpoint para obtener preguntas del quiz
-	r.GET("/api/quiz/questions", func(c *gin.Context) {
-		// Buscar el archivo all_questions.json
-		questionsFile := ""
-		candidates := []string{
-			filepath.Join("cmd", "scrape_questions", "data", "all_questions.json"),
-			filepath.Join("..", "cmd", "scrape_questions", "data", "all_questions.json"),
-			filepath.Join("..", "..", "cmd", "scrape_questions", "data", "all_questions.json"),
-		}
-
-		for _, candidate := range candidates {
-			if _, err := os.Stat(candidate); err == nil {
-				questionsFile = candidate
-				break
+		out := []transfermarkt.Player{}
+		for _, p := range t.Players {
+			if position != "" && !strings.EqualFold(p.Position, position) {
+				continue
 			}
+			if group != "" && !strings.EqualFold(p.PositionGroup, group) {
+				continue
+			}
+			out = append(out, p)
 		}
+		c.JSON(http.StatusOK, gin.H{"team": t.Name, "players": out})
+	})
 
-		if questionsFile == "" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "questions file not found",
-				"message": "El archivo all_questions.json no se encontró",
-			})
-			return
-		}
+	registerPlayerRoutes(r, players)
 
-		// Leer el archivo
-		data, err := os.ReadFile(questionsFile)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "could not read questions file",
-				"message": err.Error(),
-			})
-			return
-		}
+	// Preguntas del quiz: se cargan una vez al arrancar
+	questionsDir := findDataDir(filepath.Join("cmd", "scrape_questions", "data"))
+	quizQuestions, err := questions.Load(filepath.Join(questionsDir, "all_questions.json"))
+	if err != nil {
+		fmt.Printf("Warning: could not load quiz questions: %v\n", err)
+	}
+	quizSessions := questions.NewSessions(2 * time.Hour)
 
-		var questionsData QuizQuestionsResponse
-		if err := json.Unmarshal(data, &questionsData); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "could not parse questions file",
-				"message": err.Error(),
-			})
-			return
+	// BINGO_OFFLINE=1 sirve solo los tableros descargados, sin ir a playfootball.games
+	var bingoClient *http.Client
+	if os.Getenv("BINGO_OFFLINE") != "1" {
+		bingoClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	bingoStore := bingo.NewStore(
+		filepath.Join(findDataDir(filepath.Join("cmd", "scrape_bingo")), "data", "remote_bingo"),
+		bingoClient,
+		30*time.Minute,
+	)
+	// Juego box2box (dataGame.json y players.json); sin él sus rutas responden 404
+	box2box, err := bingo.LoadBox2Box(filepath.Join(findDataDir(filepath.Join("cmd", "scrape_bingo")), "data", "box2box"))
+	if err != nil {
+		fmt.Printf("Warning: could not load box2box game: %v\n", err)
+	}
+	registerBingoRoutes(r, bingoStore, catalogRoster(players), box2box)
+
+	// Desafío diario: DAILY_SECRET fija la semilla; sin él se usa el secreto
+	// guardado en DAILY_DIR (se crea la primera vez), así las semillas no
+	// cambian con cada reinicio
+	dailyDir := os.Getenv("DAILY_DIR")
+	if dailyDir == "" {
+		dailyDir = filepath.Join(findDataDir(filepath.Join("cmd", "api")), "data", "daily")
+	}
+	dailyStore, err := daily.NewStore(dailyDir)
+	if err != nil {
+		log.Fatalf("could not open daily store: %v", err)
+	}
+	dailySecret := []byte(os.Getenv("DAILY_SECRET"))
+	if len(dailySecret) == 0 {
+		if dailySecret, err = dailyStore.Secret(); err != nil {
+			log.Fatalf("DAILY_SECRET not set and no stored secret in %s: %v", dailyDir, err)
 		}
+		log.Printf("DAILY_SECRET not set, using the secret stored in %s", dailyDir)
+	}
+	registerDailyRoutes(r, dailyDeps{
+		secret:    dailySecret,
+		store:     dailyStore,
+		questions: quizQuestions,
+		sessions:  quizSessions,
+		players:   players,
+		roster:    catalogRoster(players),
+	})
 
-		// Obtener el parámetro count si existe
-		countParam := c.Query("count")
-
-		questions := questionsData.Questions
-
-		// Si se especifica un count, mezclar y tomar solo esa cantidad
-		if countParam != "" {
-			if count, err := strconv.Atoi(countParam); err == nil && count > 0 {
-				// Mezclar las preguntas
-				rand.Seed(time.Now().UnixNano())
-				rand.Shuffle(len(questions), func(i, j int) {
-					questions[i], questions[j] = questions[j], questions[i]
-				})
-
-				// Tomar solo la cantidad solicitada
-				if count < len(questions) {
-					questions = questions[:count]
-				}
-			}
+	// Partidas puntuadas: SESSION_SECRET firma los resultados (si falta se
+	// usa el secreto del desafío diario). Expiran tras SESSION_TTL sin
+	// actividad (por defecto 30m)
+	sessionSecret := []byte(os.Getenv("SESSION_SECRET"))
+	if len(sessionSecret) == 0 {
+		sessionSecret = dailySecret
+	}
+	sessionTTL := 30 * time.Minute
+	if v := os.Getenv("SESSION_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			sessionTTL = d
 		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"total":     len(questionsData.Questions),
-			"returned":  len(questions),
-			"questions": questions,
-		})
-	})
+	}
+	games := sessions.NewManager(sessions.NewMemoryStore(sessionTTL), sessionSecret)
+	registerSessionRoutes(r, games, sessionDealer{players: players, roster: catalogRoster(players)})
+	registerQuizRoutes(r, quizQuestions, quizSessions, games)
+
+	// Rankings: con LEADERBOARD_FILE se guardan en ese archivo y
+	// sobreviven reinicios; sin él viven solo en memoria
+	var boardStore leaderboard.Store = leaderboard.NewMemoryStore()
+	if path := os.Getenv("LEADERBOARD_FILE"); path != "" {
+		fileStore, err := leaderboard.OpenFileStore(path)
+		if err != nil {
+			log.Fatalf("could not open leaderboard file: %v", err)
+		}
+		defer fileStore.Close()
+		boardStore = fileStore
+	} else {
+		log.Println("Warning: LEADERBOARD_FILE not set, leaderboards are kept in memory only")
+	}
+	registerLeaderboardRoutes(r, leaderboard.New(boardStore), games)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"futbol912.com/catalog"
+	"futbol912.com/fuzzy"
+	"futbol912.com/transfermarkt"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxRandomPlayers = 100
+	maxSearchResults = 50
+)
+
+// playerView is a catalog player with the team it was scraped from.
+type playerView struct {
+	transfermarkt.Player
+	League   string `json:"league"`
+	Season   int    `json:"season"`
+	Team     string `json:"team"`
+	TeamFile string `json:"team_file"`
+}
+
+func newPlayerView(e catalog.Entry) playerView {
+	return playerView{
+		Player:   e.Player,
+		League:   e.Team.League,
+		Season:   e.Team.Season,
+		Team:     e.Team.Name,
+		TeamFile: e.Team.File,
+	}
+}
+
+// registerPlayerRoutes exposes player-level queries over the catalog.
+func registerPlayerRoutes(r *gin.Engine, players *catalog.Watcher) {
+	r.GET("/api/players/random", func(c *gin.Context) {
+		cat := players.Catalog()
+
+		count := 10
+		if v := c.Query("count"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid count"})
+				return
+			}
+			count = min(n, maxRandomPlayers)
+		}
+
+		f, ok := parsePlayerFilter(c, cat)
+		if !ok {
+			return
+		}
+
+		var seed int64
+		seeded := c.Query("seed") != ""
+		if seeded {
+			n, err := strconv.ParseInt(c.Query("seed"), 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid seed"})
+				return
+			}
+			seed = n
+		} else {
+			seed = time.Now().UnixNano()
+		}
+
+		matches := f.apply(cat)
+		// Partial Fisher-Yates: the first count entries are a uniform sample.
+		rng := rand.New(rand.NewSource(seed))
+		n := min(count, len(matches))
+		for i := 0; i < n; i++ {
+			j := i + rng.Intn(len(matches)-i)
+			matches[i], matches[j] = matches[j], matches[i]
+		}
+
+		out := make([]playerView, 0, n)
+		for _, e := range matches[:n] {
+			out = append(out, newPlayerView(e))
+		}
+		resp := gin.H{"total": len(matches), "returned": len(out), "players": out}
+		if seeded {
+			resp["seed"] = seed
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+
+	r.GET("/api/players/search", func(c *gin.Context) {
+		searchPlayers(c, players)
+	})
+}
+
+// searchHit is a search result with how it matched the query.
+type searchHit struct {
+	playerView
+	Match    string `json:"match"` // exact, prefix or fuzzy
+	Distance int    `json:"distance,omitempty"`
+
+	rank   fuzzy.Result
+	prefix int // fuzzy.SharedPrefix, to order matches at the same distance
+}
+
+func searchPlayers(c *gin.Context, players *catalog.Watcher) {
+	cat := players.Catalog()
+
+	q := fuzzy.Fold(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing q"})
+		return
+	}
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = min(n, maxSearchResults)
+	}
+	f, ok := parsePlayerFilter(c, cat)
+	if !ok {
+		return
+	}
+
+	hits := []searchHit{}
+	for _, e := range f.apply(cat) {
+		name := fuzzy.Fold(e.Name)
+		res := fuzzy.MatchFolded(q, name)
+		if res.Kind == fuzzy.None {
+			continue
+		}
+		hits = append(hits, searchHit{
+			playerView: newPlayerView(e),
+			Match:      res.Kind.String(),
+			Distance:   res.Distance,
+			rank:       res,
+			prefix:     fuzzy.SharedPrefix(q, name),
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.rank != b.rank {
+			return a.rank.Better(b.rank)
+		}
+		if a.prefix != b.prefix {
+			return a.prefix > b.prefix
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+
+	total := len(hits)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	c.JSON(http.StatusOK, gin.H{"query": c.Query("q"), "total": total, "returned": len(hits), "players": hits})
+}
+
+// playerFilter holds the query filters shared by the player endpoints.
+// Only the latest season of each league is searched.
+type playerFilter struct {
+	leagues        []string
+	team           string
+	nationality    string
+	minAge, maxAge int
+	hasPhoto       bool
+	hasValue       bool
+}
+
+// parsePlayerFilter reads ?league= (comma separated), ?team=, ?nationality=,
+// ?min_age=, ?max_age=, ?has_photo=1 and ?has_market_value=1, writing a 400
+// or 404 and returning false when one is invalid.
+func parsePlayerFilter(c *gin.Context, cat *catalog.Catalog) (playerFilter, bool) {
+	var f playerFilter
+	for _, k := range strings.Split(c.Query("league"), ",") {
+		if k = strings.TrimSpace(k); k == "" {
+			continue
+		}
+		if _, ok := cat.League(k); !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "league not found", "league": k})
+			return f, false
+		}
+		f.leagues = append(f.leagues, k)
+	}
+	f.team = strings.TrimSuffix(strings.TrimSpace(c.Query("team")), ".json")
+	f.nationality = strings.TrimSpace(c.Query("nationality"))
+
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"min_age", &f.minAge}, {"max_age", &f.maxAge}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.name})
+			return f, false
+		}
+		*p.dst = n
+	}
+	f.hasPhoto = queryBool(c, "has_photo")
+	f.hasValue = queryBool(c, "has_market_value")
+	return f, true
+}
+
+func queryBool(c *gin.Context, name string) bool {
+	b, _ := strconv.ParseBool(c.Query(name))
+	return b
+}
+
+// apply returns the matching entries in catalog order.
+func (f playerFilter) apply(cat *catalog.Catalog) []catalog.Entry {
+	var from []catalog.Entry
+	if f.nationality != "" {
+		wanted := map[string]bool{}
+		for _, k := range f.leagues {
+			wanted[k] = true
+		}
+		for _, e := range cat.ByNationality(f.nationality) {
+			if e.Team.Season != cat.LatestSeason(e.Team.League) {
+				continue
+			}
+			if len(wanted) > 0 && !wanted[e.Team.League] {
+				continue
+			}
+			from = append(from, e)
+		}
+	} else {
+		from = cat.Entries(f.leagues...)
+	}
+
+	out := []catalog.Entry{}
+	for _, e := range from {
+		if f.match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (f playerFilter) match(e catalog.Entry) bool {
+	if strings.TrimSpace(e.Name) == "" {
+		return false
+	}
+	if f.team != "" {
+		file := strings.TrimSuffix(e.Team.File, ".json")
+		if !strings.EqualFold(file, f.team) && !strings.EqualFold(e.Team.Name, f.team) {
+			return false
+		}
+	}
+	if f.minAge > 0 && (e.AgeYears == 0 || e.AgeYears < f.minAge) {
+		return false
+	}
+	if f.maxAge > 0 && (e.AgeYears == 0 || e.AgeYears > f.maxAge) {
+		return false
+	}
+	if f.hasPhoto && strings.TrimSpace(e.PhotoURL) == "" {
+		return false
+	}
+	if f.hasValue && e.MarketValueEUR == 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"futbol912.com/daily"
+	"futbol912.com/questions"
+	"futbol912.com/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// quizQuestionView is a question without its answers.
+type quizQuestionView struct {
+	ID         string   `json:"id"`
+	SourceID   string   `json:"source_id,omitempty"`
+	Question   string   `json:"question"`
+	Answers    int      `json:"answers"` // number of answers to find
+	Tags       []string `json:"tags"`
+	Difficulty string   `json:"difficulty"`
+}
+
+func newQuizQuestionView(q questions.Question) quizQuestionView {
+	return quizQuestionView{
+		ID:         q.ID,
+		SourceID:   q.SourceID,
+		Question:   q.Text,
+		Answers:    len(q.Answers),
+		Tags:       q.Tags,
+		Difficulty: q.Difficulty,
+	}
+}
+
+type quizGuessRequest struct {
+	Session string `json:"session" binding:"required"`
+	Guess   string `json:"guess" binding:"required"`
+}
+
+type quizSessionRequest struct {
+	Session string `json:"session" binding:"required"`
+}
+
+// registerQuizRoutes serves the list-style questions. Answers never leave
+// the server until the player gives up: fetching questions opens a session
+// and guesses are checked against it.
+func registerQuizRoutes(r *gin.Engine, all []questions.Question, quizSessions *questions.Sessions, games *sessions.Manager) {
+	byID := map[string]questions.Question{}
+	for _, q := range all {
+		byID[q.ID] = q
+	}
+
+	r.GET("/api/quiz/questions", func(c *gin.Context) {
+		if len(all) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "questions file not found",
+				"message": "El archivo all_questions.json no se encontró",
+			})
+			return
+		}
+
+		// ?tag=club:arsenal,era:2010s exige todas las etiquetas; también
+		// vale solo el valor (?tag=arsenal)
+		var tags []string
+		for _, t := range strings.Split(c.Query("tag"), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tags = append(tags, t)
+			}
+		}
+		difficulty := strings.ToLower(strings.TrimSpace(c.Query("difficulty")))
+
+		picked := []questions.Question{}
+		for _, q := range all {
+			if difficulty != "" && q.Difficulty != difficulty {
+				continue
+			}
+			matches := true
+			for _, t := range tags {
+				if !questions.HasTag(q.Tags, t) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				picked = append(picked, q)
+			}
+		}
+		// Si se especifica un count, mezclar y tomar solo esa cantidad
+		if count, err := strconv.Atoi(c.Query("count")); err == nil && count > 0 {
+			rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+			rng.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+			if count < len(picked) {
+				picked = picked[:count]
+			}
+		}
+
+		id, err := quizSessions.Start(picked)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not start quiz session", "message": err.Error()})
+			return
+		}
+		views := make([]quizQuestionView, 0, len(picked))
+		for _, q := range picked {
+			views = append(views, newQuizQuestionView(q))
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"session":   id,
+			"total":     len(all),
+			"returned":  len(views),
+			"questions": views,
+		})
+	})
+
+	r.GET("/api/quiz/questions/:id", func(c *gin.Context) {
+		q, ok := quizQuestion(c, byID)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, newQuizQuestionView(q))
+	})
+
+	r.POST("/api/quiz/questions/:id/guess", func(c *gin.Context) {
+		var req quizGuessRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "message": err.Error()})
+			return
+		}
+		q, ok := quizQuestion(c, byID)
+		if !ok {
+			return
+		}
+		res, err := quizSessions.Guess(req.Session, q, req.Guess)
+		if err != nil {
+			quizError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+
+	r.POST("/api/quiz/questions/:id/giveup", func(c *gin.Context) {
+		var req quizSessionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "message": err.Error()})
+			return
+		}
+		q, ok := quizQuestion(c, byID)
+		if !ok {
+			return
+		}
+		found, err := quizSessions.GiveUp(req.Session, q)
+		if err != nil {
+			quizError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": q.ID, "answers": q.Answers, "found": found})
+	})
+
+	// Cerrar la sesión devuelve el resultado; solo se firma para el ranking,
+	// como las partidas de /api/sessions, si es el desafío de hoy (las
+	// mismas preguntas para todos) y la sesión no se rindió en ninguna,
+	// porque rendirse muestra las respuestas. Un abandono en otra sesión
+	// no cuenta. El puntaje son las respuestas encontradas.
+	r.POST("/api/quiz/finish", func(c *gin.Context) {
+		var req quizSessionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "message": err.Error()})
+			return
+		}
+		sum, err := quizSessions.Finish(req.Session)
+		if err != nil {
+			quizError(c, err)
+			return
+		}
+		res := sessions.Result{
+			Session:    req.Session,
+			Mode:       sessions.ModeQuestions,
+			Score:      sum.Found,
+			Correct:    sum.Found,
+			Answered:   sum.Questions,
+			Rounds:     sum.Questions,
+			StartedAt:  sum.Started.UTC().Truncate(time.Millisecond),
+			FinishedAt: time.Now().UTC().Truncate(time.Millisecond),
+		}
+		resp := gin.H{"result": res, "found": sum.Found, "total": sum.Total, "ranked": false}
+		switch {
+		case sum.Set != dailyQuizSet(daily.Today()):
+			resp["message"] = "only today's daily questions count for the leaderboard"
+		case sum.GivenUp:
+			resp["message"] = "this session gave up on a question, which revealed its answers"
+		default:
+			sig, err := games.Sign(res)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			resp["signature"] = sig
+			resp["ranked"] = true
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+}
+
+func quizQuestion(c *gin.Context, byID map[string]questions.Question) (questions.Question, bool) {
+	q, ok := byID[c.Param("id")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "question not found"})
+		return questions.Question{}, false
+	}
+	return q, true
+}
+
+func quizError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, questions.ErrSessionNotFound), errors.Is(err, questions.ErrNotInSession):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, questions.ErrFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"futbol912.com/catalog"
+	"futbol912.com/countries"
+	"futbol912.com/games/bingo"
+	"futbol912.com/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Every game of a mode deals the same number of rounds, so scores on
+	// its leaderboard compare like with like.
+	sessionRounds       = 10
+	bingoSessionPlayers = 42 // as many as a downloaded board deals
+	nationalityOptions  = 4
+	teamQuizOthers      = 4 // players from other clubs next to the two teammates
+)
+
+// sessionPlayer is a player as shown in a round: nothing that gives away
+// an age, nationality or (in the team quiz) club.
+type sessionPlayer struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Team     string `json:"team,omitempty"`
+	League   string `json:"league,omitempty"`
+	Position string `json:"position,omitempty"`
+	PhotoURL string `json:"photo_url,omitempty"`
+}
+
+func newSessionPlayer(e catalog.Entry) sessionPlayer {
+	return sessionPlayer{
+		ID:       e.ID,
+		Name:     e.Name,
+		Team:     e.Team.Name,
+		League:   e.Team.League,
+		Position: e.Position,
+		PhotoURL: e.PhotoURL,
+	}
+}
+
+type sessionStartRequest struct {
+	Mode string `json:"mode" binding:"required"`
+}
+
+// sessionDealer builds a new session's rounds.
+type sessionDealer struct {
+	players *catalog.Watcher
+	roster  func() []bingo.RosterPlayer
+}
+
+// registerSessionRoutes serves scored games. The client gets one round at a
+// time and posts answers; score, streak and time bonus are kept here and
+// the final result comes back signed.
+func registerSessionRoutes(r *gin.Engine, games *sessions.Manager, d sessionDealer) {
+	r.POST("/api/sessions", func(c *gin.Context) {
+		var req sessionStartRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "message": err.Error()})
+			return
+		}
+		mode, err := sessions.ParseMode(strings.ToLower(strings.TrimSpace(req.Mode)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "modes": sessions.Modes})
+			return
+		}
+		s, board, err := d.deal(mode, sessionRounds, rand.New(rand.NewSource(time.Now().UnixNano())))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not start game session", "message": err.Error()})
+			return
+		}
+		if err := games.Start(s); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not start game session", "message": err.Error()})
+			return
+		}
+
+		resp := sessionState(s)
+		if mode == sessions.ModeBingo {
+			resp["board"] = gin.H{"id": board.ID, "cells": board.Cells, "categories": board.Categories}
+		}
+		c.JSON(http.StatusCreated, resp)
+	})
+
+	r.GET("/api/sessions/:id", func(c *gin.Context) {
+		s, err := games.Get(c.Param("id"))
+		if err != nil {
+			sessionError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, sessionState(s))
+	})
+
+	r.POST("/api/sessions/:id/answers", func(c *gin.Context) {
+		var a sessions.Answer
+		if err := c.ShouldBindJSON(&a); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "message": err.Error()})
+			return
+		}
+		s, out, err := games.Answer(c.Param("id"), a)
+		if err != nil {
+			sessionError(c, err)
+			return
+		}
+		resp := sessionState(s)
+		resp["outcome"] = out
+		c.JSON(http.StatusOK, resp)
+	})
+
+	r.POST("/api/sessions/:id/finish", func(c *gin.Context) {
+		res, sig, err := games.Finish(c.Param("id"))
+		if err != nil {
+			sessionError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"result": res, "signature": sig})
+	})
+}
+
+// sessionState is the public view of a session: the running score and the
+// prompt of the round to answer next (absent once finished).
+func sessionState(s *sessions.Session) gin.H {
+	resp := gin.H{
+		"session":     s.ID,
+		"mode":        s.Mode,
+		"rounds":      len(s.Rounds),
+		"round":       s.Current,
+		"score":       s.Score,
+		"correct":     s.Correct,
+		"streak":      s.Streak,
+		"best_streak": s.BestStreak,
+		"finished":    s.Finished(),
+	}
+	if s.TimeLimit > 0 {
+		left := max(0, s.TimeLimit-time.Since(s.StartedAt))
+		resp["time_left_ms"] = left.Milliseconds()
+	}
+	if !s.Finished() {
+		resp["prompt"] = s.Round()
+	}
+	return resp
+}
+
+// rankedRounds is the number of rounds of every game of mode, and so of
+// every result its leaderboard takes.
+func rankedRounds(mode sessions.Mode) int {
+	switch mode {
+	case sessions.ModeQuestions:
+		return dailyQuestions
+	case sessions.ModeBingo:
+		return bingoSessionPlayers
+	}
+	return sessionRounds
+}
+
+// deal picks the rounds of a new session from the current catalog.
+func (d sessionDealer) deal(mode sessions.Mode, n int, rng *rand.Rand) (*sessions.Session, bingo.Board, error) {
+	var (
+		rounds []sessions.Round
+		board  bingo.Board
+		err    error
+	)
+	switch mode {
+	case sessions.ModeAge:
+		rounds, err = d.ageRounds(n, rng)
+	case sessions.ModeNationality:
+		rounds, err = d.nationalityRounds(n, rng)
+	case sessions.ModeTeam:
+		rounds, err = d.teamRounds(n, rng)
+	case sessions.ModeBingo:
+		board, err = bingo.Generate(d.roster(), bingo.GenerateOptions{Seed: rng.Int63(), Players: bingoSessionPlayers})
+		if err == nil {
+			rounds, err = bingoRounds(board)
+		}
+	}
+	if err != nil {
+		return nil, bingo.Board{}, err
+	}
+	if len(rounds) != rankedRounds(mode) {
+		return nil, bingo.Board{}, fmt.Errorf("not enough players for mode %s", mode)
+	}
+
+	s, err := sessions.New(mode, rounds, time.Now())
+	if err != nil {
+		return nil, bingo.Board{}, err
+	}
+	if mode == sessions.ModeBingo {
+		s.Cells = board.Cells
+		s.Used = make([]bool, len(board.Cells))
+		s.TimeLimit = sessions.BingoTimeLimit
+	}
+	return s, board, nil
+}
+
+// sample returns up to n entries passing keep, in random order.
+func sample(entries []catalog.Entry, n int, rng *rand.Rand, keep func(catalog.Entry) bool) []catalog.Entry {
+	var pool []catalog.Entry
+	for _, e := range entries {
+		if e.ID != "" && e.Name != "" && keep(e) {
+			pool = append(pool, e)
+		}
+	}
+	n = min(n, len(pool))
+	for i := 0; i < n; i++ {
+		j := i + rng.Intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return pool[:n]
+}
+
+func (d sessionDealer) ageRounds(n int, rng *rand.Rand) ([]sessions.Round, error) {
+	var rounds []sessions.Round
+	for _, e := range sample(d.players.Catalog().Entries(), n, rng, func(e catalog.Entry) bool {
+		return e.AgeYears > 0
+	}) {
+		prompt, err := json.Marshal(gin.H{"player": newSessionPlayer(e)})
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, sessions.Round{Prompt: prompt, Key: sessions.Key{Age: e.AgeYears}})
+	}
+	return rounds, nil
+}
+
+func (d sessionDealer) nationalityRounds(n int, rng *rand.Rand) ([]sessions.Round, error) {
+	entries := d.players.Catalog().Entries()
+	nationalities := func(e catalog.Entry) []string {
+		var out []string
+		for _, n := range e.Nationalities {
+			if n = countries.Canonical(n); n != "" {
+				out = append(out, n)
+			}
+		}
+		return out
+	}
+
+	// Wrong options come from the nationalities in the data, sorted so
+	// the draw only depends on rng.
+	seen := map[string]bool{}
+	var all []string
+	for _, e := range entries {
+		for _, n := range nationalities(e) {
+			if !seen[n] {
+				seen[n] = true
+				all = append(all, n)
+			}
+		}
+	}
+	sort.Strings(all)
+
+	var rounds []sessions.Round
+	for _, e := range sample(entries, n, rng, func(e catalog.Entry) bool {
+		return e.PhotoURL != "" && len(nationalities(e)) > 0
+	}) {
+		accept := nationalities(e)
+		options := []string{accept[0]}
+		for _, i := range rng.Perm(len(all)) {
+			if len(options) == nationalityOptions {
+				break
+			}
+			if !contains(accept, all[i]) {
+				options = append(options, all[i])
+			}
+		}
+		rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+		prompt, err := json.Marshal(gin.H{"player": newSessionPlayer(e), "options": options})
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, sessions.Round{Prompt: prompt, Key: sessions.Key{Accept: accept}})
+	}
+	return rounds, nil
+}
+
+// teamRounds deals two teammates among players from other clubs, each from
+// a different one, so exactly one pair shares a club.
+func (d sessionDealer) teamRounds(n int, rng *rand.Rand) ([]sessions.Round, error) {
+	byTeam := map[*catalog.Team][]catalog.Entry{}
+	var teams []*catalog.Team
+	for _, e := range d.players.Catalog().Entries() {
+		if e.ID == "" || e.Name == "" {
+			continue
+		}
+		if _, ok := byTeam[e.Team]; !ok {
+			teams = append(teams, e.Team)
+		}
+		byTeam[e.Team] = append(byTeam[e.Team], e)
+	}
+	var targets []*catalog.Team
+	for _, t := range teams {
+		if len(byTeam[t]) >= 2 {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 || len(teams) < teamQuizOthers+1 {
+		return nil, nil
+	}
+
+	var rounds []sessions.Round
+	for len(rounds) < n {
+		target := targets[rng.Intn(len(targets))]
+		var others []*catalog.Team
+		for _, i := range rng.Perm(len(teams)) {
+			if teams[i] != target && len(others) < teamQuizOthers {
+				others = append(others, teams[i])
+			}
+		}
+		mates := sample(byTeam[target], 2, rng, func(catalog.Entry) bool { return true })
+		picked := append([]catalog.Entry(nil), mates...)
+		for _, t := range others {
+			picked = append(picked, byTeam[t][rng.Intn(len(byTeam[t]))])
+		}
+		rng.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+
+		views := make([]sessionPlayer, 0, len(picked))
+		for _, e := range picked {
+			v := newSessionPlayer(e)
+			v.Team, v.League = "", ""
+			views = append(views, v)
+		}
+		prompt, err := json.Marshal(gin.H{"players": views})
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, sessions.Round{
+			Prompt: prompt,
+			Key:    sessions.Key{Players: []string{mates[0].ID, mates[1].ID}},
+		})
+	}
+	return rounds, nil
+}
+
+// bingoRounds deals the board's players in order; each round places one.
+func bingoRounds(board bingo.Board) ([]sessions.Round, error) {
+	var rounds []sessions.Round
+	for _, p := range board.Players {
+		prompt, err := json.Marshal(gin.H{"player": bingoPlayerView{ID: p.ID, Name: p.Name}})
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, sessions.Round{Prompt: prompt, Key: sessions.Key{Categories: p.CategoryIDs}})
+	}
+	return rounds, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sessions.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, sessions.ErrFinished), errors.Is(err, sessions.ErrWrongRound):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, sessions.ErrInvalidAnswer):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "game session error", "message": err.Error()})
+	}
+}
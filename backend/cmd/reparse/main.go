@@ -0,0 +1,176 @@
+// Command reparse rebuilds team JSONs from the snapshot archive that
+// `scrape -archive` fills, without touching the network: the competition
+// page and every squad page come from their latest snapshot (or the latest
+// one at -at), through the same parser the scraper uses. Snapshots copied
+// from elsewhere can be added with -import; they are filed by the URL and
+// fetch time in their gzip header.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"futbol912.com/leagues"
+	"futbol912.com/snapshot"
+	"futbol912.com/transfermarkt"
+)
+
+var registry *leagues.Registry
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Uso: reparse [flags] <liga> [<liga>...]\n\nLigas (clave o código):\n")
+	if registry != nil {
+		for _, l := range registry.Leagues {
+			fmt.Fprintf(os.Stderr, "  %-12s %-4s %s\n", l.Key, l.Code, l.Name)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	archiveFlags := snapshot.RegisterFlags(flag.CommandLine)
+	at := flag.String("at", "", "use the snapshots taken at or before this time, as YYYY-MM-DD (end of that day, UTC) or RFC 3339 (default: the latest)")
+	outDir := flag.String("out", "", "output directory (default: <league dir>/<season> from the registry; only with one league)")
+	season := flag.Int("season", 0, "season to rebuild, as the year it starts (default: the league's season in the registry)")
+	concurrency := flag.Int("concurrency", 4, "teams parsed at the same time")
+	imports := flag.String("import", "", "comma-separated snapshot files to add to the archive first, filed by the URL and time in their gzip header")
+	config := flag.String("config", "", "league registry file (default: leagues.json found from the working directory, or $LEAGUES_FILE)")
+	flag.Usage = usage
+	flag.Parse()
+
+	path := *config
+	if path == "" {
+		var err error
+		if path, err = leagues.Locate(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	var err error
+	registry, err = leagues.Load(path)
+	if err != nil {
+		log.Fatalf("failed to load league registry: %v", err)
+	}
+
+	if flag.NArg() == 0 || *outDir != "" && flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	if archiveFlags.Dir == "" {
+		fmt.Fprintln(os.Stderr, "-archive (or $SNAPSHOT_DIR) is required")
+		os.Exit(2)
+	}
+	until, err := parseAt(*at)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -at %q: %v\n", *at, err)
+		os.Exit(2)
+	}
+	var selected []leagues.League
+	for _, arg := range flag.Args() {
+		league, ok := registry.Get(arg)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown league %q\n", arg)
+			usage()
+			os.Exit(2)
+		}
+		if *season > 0 {
+			league.Season = *season
+		}
+		selected = append(selected, league)
+	}
+
+	archive, err := archiveFlags.Open()
+	if err != nil {
+		log.Fatalf("failed to open snapshot archive: %v", err)
+	}
+	if *imports != "" {
+		if err := importSnapshots(archive, strings.Split(*imports, ",")); err != nil {
+			log.Fatal(err)
+		}
+	}
+	replay := archive.Replay(until)
+
+	var jobs []transfermarkt.Job
+	for _, league := range selected {
+		sc := transfermarkt.NewScraper(league)
+		sc.Client.Transport = replay
+		// A missing snapshot won't appear on a retry.
+		sc.Retry.MaxAttempts = 1
+
+		teams, err := sc.DiscoverTeams()
+		if err != nil {
+			log.Printf("no usable %s competition page in the archive: %v", league.Key, err)
+			continue
+		}
+		fmt.Printf("%s %d: %d team(s) in the archived competition page\n", league.Name, league.Season, len(teams))
+		out := outputDir(*outDir, league)
+		for _, t := range teams {
+			jobs = append(jobs, transfermarkt.Job{Scraper: sc, Team: t, OutDir: out})
+		}
+	}
+
+	failed := rebuild(jobs, *concurrency)
+	fmt.Printf("%d team(s) rebuilt, %d without a usable snapshot\n", len(jobs)-failed, failed)
+	if failed > 0 || len(jobs) == 0 {
+		os.Exit(1)
+	}
+}
+
+// importSnapshots adds snapshot files to the archive under the URL and
+// time their header records.
+func importSnapshots(archive *snapshot.Archive, paths []string) error {
+	for _, p := range paths {
+		snap, err := archive.Import(strings.TrimSpace(p))
+		if err != nil {
+			return fmt.Errorf("failed to import snapshot: %w", err)
+		}
+		fmt.Printf("Imported %s, fetched %s\n", snap.URL, snap.At.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// rebuild parses and saves every job's team and returns how many failed.
+func rebuild(jobs []transfermarkt.Job, concurrency int) int {
+	failed := 0
+	orch := transfermarkt.Orchestrator{
+		Concurrency: concurrency,
+		Done: func(r transfermarkt.JobResult) {
+			if r.Err != nil {
+				failed++
+				log.Printf("skipping %s/%s: %v", r.Scraper.League.Key, r.Team.Slug, r.Err)
+				return
+			}
+			fmt.Printf("Rebuilt %s players: %d\n", r.Path(), r.Players)
+		},
+	}
+	orch.Run(jobs)
+	return failed
+}
+
+// parseAt reads -at. A bare date means the end of that day, so every
+// snapshot taken that day counts.
+func parseAt(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// outputDir is -out, or the league's season directory, created if needed.
+func outputDir(flagOut string, league leagues.League) string {
+	out := flagOut
+	if out == "" {
+		out = registry.SeasonDir(league, league.Season)
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		log.Fatalf("failed to create out dir: %v", err)
+	}
+	return out
+}
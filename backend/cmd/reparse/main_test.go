@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"futbol912.com/leagues"
+	"futbol912.com/snapshot"
+	"futbol912.com/transfermarkt"
+)
+
+// fixture is a squad page archived by `scrape -archive`; its gzip header
+// holds the page's URL and fetch time.
+const fixture = "../../transfermarkt/testdata/kader_es_real-madrid.html.gz"
+
+// TestReparseSnapshotHeader imports the fixture and rebuilds its team from
+// it: the snapshot must be filed, and replayed, by its header.
+func TestReparseSnapshotHeader(t *testing.T) {
+	archive, err := snapshot.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := importSnapshots(archive, []string{fixture}); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := transfermarkt.NewScraper(leagues.League{Key: "laligaes", Host: "www.transfermarkt.es", Path: "laliga", Code: "ES1", Season: 2025})
+	url := sc.SquadURL("real-madrid", "418")
+	list, err := archive.List(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("%d snapshots of %s in the archive, want the imported one", len(list), url)
+	}
+	fetched := time.Date(2026, 10, 16, 23, 46, 11, 0, time.UTC)
+	if !list[0].At.Equal(fetched) {
+		t.Errorf("snapshot filed at %s, want the header's fetch time %s", list[0].At, fetched)
+	}
+
+	team := transfermarkt.Team{Slug: "real-madrid", URL: url}
+	rebuildAt := func(at time.Time) (string, int) {
+		sc.Client.Transport = archive.Replay(at)
+		sc.Retry.MaxAttempts = 1
+		job := transfermarkt.Job{Scraper: sc, Team: team, OutDir: t.TempDir()}
+		return job.Path(), rebuild([]transfermarkt.Job{job}, 1)
+	}
+
+	path, failed := rebuildAt(fetched)
+	if failed != 0 {
+		t.Fatalf("rebuilding from the snapshot failed")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Players []transfermarkt.Player `json:"players"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Players) != 4 {
+		t.Errorf("rebuilt %d players, want the 4 on the archived page", len(out.Players))
+	}
+
+	// Before its fetch time the page wasn't archived yet.
+	if path, failed := rebuildAt(fetched.Add(-time.Second)); failed != 1 {
+		t.Errorf("a rebuild from before the fetch time succeeded")
+	} else if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a failed rebuild wrote %s", filepath.Base(path))
+	}
+}
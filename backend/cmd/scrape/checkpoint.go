@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"futbol912.com/transfermarkt"
+)
+
+// checkpointFile sits next to the team files. It has no .json suffix so
+// the catalog and the bingo roster loader don't take it for a team.
+const checkpointFile = ".checkpoint"
+
+const (
+	statusPending = "pending"
+	statusOK      = "ok"
+	statusFailed  = "failed"
+)
+
+// teamStatus is one team's line in the checkpoint. Attempts add up across
+// runs.
+type teamStatus struct {
+	URL       string    `json:"url"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	Players   int       `json:"players,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	Updated   time.Time `json:"updated"`
+}
+
+// checkpoint records the progress of one league season's scrape so an
+// interrupted run can pick up where it stopped.
+type checkpoint struct {
+	League  string                 `json:"league"`
+	Season  int                    `json:"season"`
+	Started time.Time              `json:"started"`
+	Teams   map[string]*teamStatus `json:"teams"` // by slug
+
+	path string
+}
+
+// loadCheckpoint reads the checkpoint in dir, or returns an empty one if
+// there is none or it belongs to another league or season.
+func loadCheckpoint(dir, league string, season int) (*checkpoint, error) {
+	cp := &checkpoint{League: league, Season: season, Teams: map[string]*teamStatus{}, path: filepath.Join(dir, checkpointFile)}
+	b, err := os.ReadFile(cp.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved checkpoint
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil, err
+	}
+	if saved.League != league || saved.Season != season || saved.Teams == nil {
+		return cp, nil
+	}
+	saved.path = cp.path
+	return &saved, nil
+}
+
+// reset starts a fresh run over teams, keeping attempt counts.
+func (cp *checkpoint) reset(teams []transfermarkt.Team) {
+	old := cp.Teams
+	cp.Started = time.Now().UTC()
+	cp.Teams = map[string]*teamStatus{}
+	for _, t := range teams {
+		st := &teamStatus{URL: t.URL, Status: statusPending, Updated: cp.Started}
+		if prev, ok := old[t.Slug]; ok {
+			st.Attempts = prev.Attempts
+		}
+		cp.Teams[t.Slug] = st
+	}
+}
+
+// teams returns the recorded teams whose status is one of statuses, sorted
+// by slug.
+func (cp *checkpoint) teams(statuses ...string) []transfermarkt.Team {
+	var out []transfermarkt.Team
+	for slug, st := range cp.Teams {
+		for _, s := range statuses {
+			if st.Status == s {
+				out = append(out, transfermarkt.Team{Slug: slug, URL: st.URL})
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slug < out[j].Slug })
+	return out
+}
+
+// record stores a finished job.
+func (cp *checkpoint) record(r transfermarkt.JobResult) {
+	st, ok := cp.Teams[r.Team.Slug]
+	if !ok {
+		st = &teamStatus{URL: r.Team.URL}
+		cp.Teams[r.Team.Slug] = st
+	}
+	st.Attempts += r.Attempts
+	st.Updated = time.Now().UTC()
+	if r.Err != nil {
+		st.Status = statusFailed
+		st.LastError = r.Err.Error()
+		return
+	}
+	st.Status = statusOK
+	st.Players = r.Players
+	st.LastError = ""
+}
+
+// save writes the checkpoint atomically, so a crash mid-write leaves the
+// previous one.
+func (cp *checkpoint) save() error {
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cp.path), checkpointFile+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cp.path)
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"futbol912.com/httpcache"
+	"futbol912.com/leagues"
+	"futbol912.com/snapshot"
+	"futbol912.com/transfermarkt"
+)
+
+var registry *leagues.Registry
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Uso: scrape [flags] <liga> [<liga>...]\n\nLigas (clave o código):\n")
+	if registry != nil {
+		for _, l := range registry.Leagues {
+			fmt.Fprintf(os.Stderr, "  %-12s %-4s %s\n", l.Key, l.Code, l.Name)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	all := flag.Bool("all", os.Getenv("SCRAPE_ALL") == "1", "scrape every team (by default only the first one of each league, to validate filenames)")
+	team := flag.String("team", "", "scrape a single squad URL and write players_index.json to -out (default: the working directory) instead of the whole league")
+	outDir := flag.String("out", "", "output directory (default: <league dir>/<season> from the registry; only with one league)")
+	season := flag.Int("season", 0, "season to scrape, as the year it starts (default: the league's season in the registry)")
+	concurrency := flag.Int("concurrency", 4, "teams scraped at the same time")
+	rate := flag.Float64("rate", 0.5, "requests per second per host, shared by all teams (0 = no limit)")
+	burst := flag.Int("burst", 1, "requests a host may get back to back before -rate applies")
+	resume := flag.Bool("resume", false, "skip teams the last run of each league already saved (see the .checkpoint file in the output directory)")
+	onlyFailed := flag.Bool("only-failed", false, "only retry the teams the last run failed")
+	cacheFlags := httpcache.RegisterFlags(flag.CommandLine)
+	archiveFlags := snapshot.RegisterFlags(flag.CommandLine)
+	config := flag.String("config", "", "league registry file (default: leagues.json found from the working directory, or $LEAGUES_FILE)")
+	flag.Usage = usage
+	flag.Parse()
+
+	path := *config
+	if path == "" {
+		var err error
+		if path, err = leagues.Locate(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	var err error
+	registry, err = leagues.Load(path)
+	if err != nil {
+		log.Fatalf("failed to load league registry: %v", err)
+	}
+
+	if flag.NArg() == 0 || (*team != "" || *outDir != "") && flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	var selected []leagues.League
+	for _, arg := range flag.Args() {
+		league, ok := registry.Get(arg)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown league %q\n", arg)
+			usage()
+			os.Exit(2)
+		}
+		if *season > 0 {
+			league.Season = *season
+		}
+		selected = append(selected, league)
+	}
+
+	// One limiter for every league: .com and .es get a bucket each, and
+	// concurrent teams share it instead of multiplying the rate.
+	limiter := transfermarkt.NewLimiter(*rate, *burst)
+	cache, err := cacheFlags.Open()
+	if err != nil {
+		log.Fatalf("failed to open http cache: %v", err)
+	}
+	archive, err := archiveFlags.Open()
+	if err != nil {
+		log.Fatalf("failed to open snapshot archive: %v", err)
+	}
+
+	if *team != "" {
+		sc := transfermarkt.NewScraper(selected[0])
+		sc.Limiter = limiter
+		sc.UseCache(cache)
+		sc.UseArchive(archive)
+		// The index isn't a team file, so keep it out of the season
+		// directory the catalog loads.
+		out := *outDir
+		if out == "" {
+			out = "."
+		}
+		if err := os.MkdirAll(out, 0o755); err != nil {
+			log.Fatalf("failed to create out dir: %v", err)
+		}
+		fmt.Println("Scraping:", *team)
+		players, err := sc.ScrapeClubRoster(*team)
+		if err != nil {
+			log.Fatalf("scrape failed: %v", err)
+		}
+		path := filepath.Join(out, "players_index.json")
+		if err := transfermarkt.SavePlayersIndex(players, path); err != nil {
+			log.Fatalf("save failed: %v", err)
+		}
+		fmt.Println("Saved", path, "entries:", len(players))
+		return
+	}
+
+	var jobs []transfermarkt.Job
+	checkpoints := map[string]*checkpoint{} // by league key
+	for _, league := range selected {
+		sc := transfermarkt.NewScraper(league)
+		sc.Limiter = limiter
+		sc.UseCache(cache)
+		sc.UseArchive(archive)
+		out := outputDir(*outDir, league)
+
+		cp, err := loadCheckpoint(out, league.Key, league.Season)
+		if err != nil {
+			log.Fatalf("failed to read %s checkpoint: %v", league.Key, err)
+		}
+		checkpoints[league.Key] = cp
+
+		var teams []transfermarkt.Team
+		switch {
+		case *onlyFailed:
+			teams = cp.teams(statusFailed)
+			fmt.Printf("%s: retrying %d failed team(s)\n", league.Name, len(teams))
+		case *resume && len(cp.Teams) > 0:
+			teams = cp.teams(statusPending, statusFailed)
+			fmt.Printf("%s: resuming, %d of %d team(s) left\n", league.Name, len(teams), len(cp.Teams))
+		default:
+			// A failed discovery keeps the previous run's record.
+			if teams = discover(sc, *all); len(teams) > 0 {
+				cp.reset(teams)
+			}
+		}
+		if err := cp.save(); err != nil {
+			log.Fatalf("failed to write %s checkpoint: %v", league.Key, err)
+		}
+		for _, t := range teams {
+			jobs = append(jobs, transfermarkt.Job{Scraper: sc, Team: t, OutDir: out})
+		}
+	}
+	if len(jobs) == 0 {
+		printSummary(selected, checkpoints, nil)
+		return
+	}
+
+	fmt.Printf("Scraping %d team(s), %d at a time, %.2f req/s per host\n", len(jobs), *concurrency, *rate)
+	orch := transfermarkt.Orchestrator{
+		Concurrency: *concurrency,
+		Done: func(r transfermarkt.JobResult) {
+			cp := checkpoints[r.Scraper.League.Key]
+			cp.record(r)
+			if err := cp.save(); err != nil {
+				log.Printf("failed to write %s checkpoint: %v", r.Scraper.League.Key, err)
+			}
+			if r.Err != nil {
+				log.Printf("giving up on %s/%s: %v", r.Scraper.League.Key, r.Team.Slug, r.Err)
+				return
+			}
+			fmt.Printf("Saved %s players: %d (%s)\n", r.Path(), r.Players, r.Elapsed.Round(time.Second))
+		},
+	}
+	results := orch.Run(jobs)
+	if failed := printSummary(selected, checkpoints, results); failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// discover lists the league's teams, or only the first one without -all.
+// A league whose competition page can't be fetched yields no teams.
+func discover(sc *transfermarkt.Scraper, all bool) []transfermarkt.Team {
+	league := sc.League
+	fmt.Printf("Discovering %s %d teams from: %s\n", league.Name, league.Season, transfermarkt.CompetitionURL(league))
+	teams, err := sc.DiscoverTeams()
+	if err != nil {
+		log.Printf("failed to fetch %s competition page: %v", league.Key, err)
+		return nil
+	}
+	slugs := make([]string, 0, len(teams))
+	for _, t := range teams {
+		slugs = append(slugs, t.Slug)
+	}
+	fmt.Printf("Found %d team(s): %v\n", len(teams), slugs)
+
+	if !all && len(teams) > 1 {
+		teams = teams[:1]
+		fmt.Println("-all not set — will only scrape the first team to validate filenames. Use -all (or SCRAPE_ALL=1) to scrape all teams.")
+	}
+	return teams
+}
+
+// printSummary prints every team of the checkpoints, marking those this
+// run didn't touch, and returns how many are not ok.
+func printSummary(selected []leagues.League, checkpoints map[string]*checkpoint, results []transfermarkt.JobResult) int {
+	ran := map[string]transfermarkt.JobResult{}
+	for _, r := range results {
+		ran[r.Scraper.League.Key+"/"+r.Team.Slug] = r
+	}
+
+	counts := map[string]int{}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "LEAGUE\tTEAM\tSTATUS\tATTEMPTS\tPLAYERS\tTIME\tERROR")
+	for _, league := range selected {
+		cp := checkpoints[league.Key]
+		slugs := make([]string, 0, len(cp.Teams))
+		for slug := range cp.Teams {
+			slugs = append(slugs, slug)
+		}
+		sort.Strings(slugs)
+		for _, slug := range slugs {
+			st := cp.Teams[slug]
+			counts[st.Status]++
+			status, elapsed := st.Status, "-"
+			if r, ok := ran[league.Key+"/"+slug]; ok {
+				elapsed = r.Elapsed.Round(time.Second).String()
+			} else {
+				status += " (skipped)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n", league.Key, slug, status, st.Attempts, st.Players, elapsed, st.LastError)
+		}
+	}
+	w.Flush()
+	fmt.Printf("\n%d ok, %d failed, %d pending\n", counts[statusOK], counts[statusFailed], counts[statusPending])
+	return counts[statusFailed] + counts[statusPending]
+}
+
+// outputDir is -out, or the league's season directory, created if needed.
+func outputDir(flagOut string, league leagues.League) string {
+	out := flagOut
+	if out == "" {
+		out = registry.SeasonDir(league, league.Season)
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		log.Fatalf("failed to create out dir: %v", err)
+	}
+	return out
+}
@@ -5,15 +5,24 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"futbol912.com/games/bingo"
+	"futbol912.com/httpcache"
+	"futbol912.com/snapshot"
 )
 
 func main() {
 	start := flag.Int("start", 720, "start id")
 	end := flag.Int("end", 730, "end id (inclusive)")
 	outDir := flag.String("out", "data/remote_bingo", "output directory (relative to current working dir or absolute)")
+	verify := flag.Bool("verify", false, "check that every downloaded board in -out is solvable instead of downloading")
+	cacheFlags := httpcache.RegisterFlags(flag.CommandLine)
+	archiveFlags := snapshot.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
 	cwd, err := os.Getwd()
@@ -27,15 +36,32 @@ func main() {
 	} else {
 		fullOut = filepath.Join(cwd, *outDir)
 	}
+
+	if *verify {
+		os.Exit(verifyBoards(fullOut))
+	}
+
 	if err := os.MkdirAll(fullOut, 0o755); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create out dir: %v\n", err)
 		os.Exit(1)
 	}
 
+	cache, err := cacheFlags.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open http cache: %v\n", err)
+		os.Exit(1)
+	}
+	archive, err := archiveFlags.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open snapshot archive: %v\n", err)
+		os.Exit(1)
+	}
+	client := archive.Client(cache, 30*time.Second)
+
 	for id := *start; id <= *end; id++ {
 		url := fmt.Sprintf("https://playfootball.games/api/football-bingo/%d.json", id)
 		fmt.Printf("Fetching %s\n", url)
-		resp, err := http.Get(url)
+		resp, err := client.Get(url)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error fetching %d: %v\n", id, err)
 			continue
@@ -68,3 +94,57 @@ func main() {
 		fmt.Printf("Saved %s\n", outPath)
 	}
 }
+
+// verifyBoards solves every board in dir and prints a solvability report.
+// It returns the process exit code: 1 when any board is unsolvable.
+func verifyBoards(dir string) int {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "no boards found in %s\n", dir)
+		return 1
+	}
+	sort.Strings(paths)
+
+	broken := 0
+	for _, p := range paths {
+		board, err := bingo.LoadBoardFile(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skip %s: %v\n", p, err)
+			continue
+		}
+		rep := bingo.Solve(board)
+		if rep.Solvable {
+			fmt.Printf("%d: ok, %d cells, %d players, solvable with the first %d\n",
+				rep.BoardID, rep.Cells, len(board.Players), rep.MinPlayers)
+			continue
+		}
+
+		broken++
+		fmt.Printf("%d: UNSOLVABLE, covers %d/%d cells with %d players\n",
+			rep.BoardID, rep.Covered, rep.Cells, len(board.Players))
+		for _, ci := range rep.DeadCells {
+			fmt.Printf("    no player satisfies cell %d: %s\n", ci, cellName(board, ci))
+		}
+	}
+
+	fmt.Printf("%d board(s) checked, %d unsolvable\n", len(paths), broken)
+	if broken > 0 {
+		return 1
+	}
+	return 0
+}
+
+func cellName(b bingo.Board, ci int) string {
+	names := make([]string, 0, len(b.Cells[ci]))
+	for _, id := range b.Cells[ci] {
+		name := fmt.Sprintf("#%d", id)
+		for _, c := range b.Categories {
+			if c.ID == id {
+				name = c.Name
+				break
+			}
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " + ")
+}
@@ -9,65 +9,40 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-)
-
-type Question struct {
-	GameData struct {
-		Question string   `json:"question"`
-		Answers  []string `json:"answers"`
-	} `json:"gameData"`
-}
+	"time"
 
-type QuestionsCollection struct {
-	Questions []Question `json:"questions"`
-}
+	"futbol912.com/httpcache"
+	"futbol912.com/questions"
+	"futbol912.com/snapshot"
+)
 
 func combineQuestions(dir string) error {
-	var allQuestions QuestionsCollection
-
-	files, err := os.ReadDir(dir)
-	if err != nil {
-		return fmt.Errorf("error al leer el directorio: %v", err)
+	outputFile := filepath.Join(dir, "..", "all_questions.json")
+	rep, err := questions.CombineDir(dir, outputFile)
+	for _, id := range rep.Invalid {
+		fmt.Printf("Pregunta %s sin texto o sin respuestas, se omite\n", id)
 	}
-
-	for _, file := range files {
-		if filepath.Ext(file.Name()) == ".json" {
-			content, err := os.ReadFile(filepath.Join(dir, file.Name()))
-			if err != nil {
-				fmt.Printf("Error al leer el archivo %s: %v\n", file.Name(), err)
-				continue
-			}
-
-			var question Question
-			if err := json.Unmarshal(content, &question); err != nil {
-				fmt.Printf("Error al decodificar el JSON de %s: %v\n", file.Name(), err)
-				continue
-			}
-
-			allQuestions.Questions = append(allQuestions.Questions, question)
-		}
+	for _, d := range rep.Duplicates {
+		fmt.Printf("Pregunta repetida %s.json (igual a %s.json), se omite\n", d.SourceID, d.KeptFrom)
 	}
-
-	jsonData, err := json.MarshalIndent(allQuestions, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error al convertir a JSON: %v", err)
+	for _, nd := range rep.NearDuplicates {
+		fmt.Printf("Preguntas casi iguales (%d cambios), revisar:\n  %s.json: %s\n  %s.json: %s\n",
+			nd.Distance, nd.A.SourceID, nd.A.Text, nd.B.SourceID, nd.B.Text)
 	}
-
-	outputFile := filepath.Join(dir, "..", "all_questions.json")
-	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
-		return fmt.Errorf("error al guardar el archivo: %v", err)
+	if err != nil {
+		return fmt.Errorf("error al combinar: %v", err)
 	}
 
 	fmt.Printf("Se han combinado todas las preguntas en %s\n", outputFile)
-	fmt.Printf("Total de preguntas procesadas: %d\n", len(allQuestions.Questions))
+	fmt.Printf("Nuevas: %d, actualizadas: %d, sin cambios: %d\n", rep.Added, rep.Updated, rep.Unchanged)
 	return nil
 }
 
-func downloadQuestions(start, end int, outDir string) error {
+func downloadQuestions(client *http.Client, start, end int, outDir string) error {
 	for id := start; id <= end; id++ {
 		url := fmt.Sprintf("https://playfootball.games/api/futbol-list-a/%d.json", id)
 		fmt.Printf("Fetching %s\n", url)
-		resp, err := http.Get(url)
+		resp, err := client.Get(url)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error fetching %d: %v\n", id, err)
 			continue
@@ -107,8 +82,21 @@ func main() {
 	end := flag.Int("end", 144, "end id (inclusive)")
 	outDir := flag.String("out", "data/remote_q", "output directory (relative to current working dir or absolute)")
 	shouldCombine := flag.Bool("combine", false, "combinar todos los archivos JSON en uno solo")
+	cacheFlags := httpcache.RegisterFlags(flag.CommandLine)
+	archiveFlags := snapshot.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
+	cache, err := cacheFlags.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open http cache: %v\n", err)
+		os.Exit(1)
+	}
+	archive, err := archiveFlags.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open snapshot archive: %v\n", err)
+		os.Exit(1)
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed get cwd: %v\n", err)
@@ -129,7 +117,8 @@ func main() {
 
 	// Primero descargamos los archivos
 	if *start <= *end {
-		if err := downloadQuestions(*start, *end, fullOut); err != nil {
+		client := archive.Client(cache, 30*time.Second)
+		if err := downloadQuestions(client, *start, *end, fullOut); err != nil {
 			fmt.Fprintf(os.Stderr, "Error descargando preguntas: %v\n", err)
 			os.Exit(1)
 		}
@@ -0,0 +1,90 @@
+// Package countries maps the nationality names found in Transfermarkt
+// rosters to a single spelling. The .es pages use Spanish names ("Brasil",
+// "Países Bajos") while the .com pages use English ones; Canonical returns
+// the English form used by transfermarkt.com.
+package countries
+
+import "strings"
+
+var spanish = map[string]string{
+	"Alemania":                 "Germany",
+	"Arabia Saudita":           "Saudi Arabia",
+	"Argelia":                  "Algeria",
+	"Benín":                    "Benin",
+	"Bosnia y Herzegovina":     "Bosnia-Herzegovina",
+	"Brasil":                   "Brazil",
+	"Bélgica":                  "Belgium",
+	"Cabo Verde":               "Cape Verde",
+	"Camerún":                  "Cameroon",
+	"Canadá":                   "Canada",
+	"Chipre":                   "Cyprus",
+	"Comoras":                  "Comoros",
+	"Corea del Sur":            "Korea, South",
+	"Costa de Marfil":          "Cote d'Ivoire",
+	"Croacia":                  "Croatia",
+	"Dinamarca":                "Denmark",
+	"Egipto":                   "Egypt",
+	"Escocia":                  "Scotland",
+	"Eslovaquia":               "Slovakia",
+	"Eslovenia":                "Slovenia",
+	"España":                   "Spain",
+	"Estados Unidos":           "United States",
+	"Finlandia":                "Finland",
+	"Francia":                  "France",
+	"Gabón":                    "Gabon",
+	"Gales":                    "Wales",
+	"Gambia":                   "The Gambia",
+	"Grecia":                   "Greece",
+	"Guadalupe":                "Guadeloupe",
+	"Guayana Francesa":         "French Guiana",
+	"Guinea Ecuatorial":        "Equatorial Guinea",
+	"Haití":                    "Haiti",
+	"Hungría":                  "Hungary",
+	"Inglaterra":               "England",
+	"Irak":                     "Iraq",
+	"Irlanda":                  "Ireland",
+	"Irán":                     "Iran",
+	"Islandia":                 "Iceland",
+	"Italia":                   "Italy",
+	"Japón":                    "Japan",
+	"Jordania":                 "Jordan",
+	"Libia":                    "Libya",
+	"Lituania":                 "Lithuania",
+	"Luxemburgo":               "Luxembourg",
+	"Macedonia del Norte":      "North Macedonia",
+	"Malasia":                  "Malaysia",
+	"Malí":                     "Mali",
+	"Marruecos":                "Morocco",
+	"Moldavia":                 "Moldova",
+	"México":                   "Mexico",
+	"Noruega":                  "Norway",
+	"Níger":                    "Niger",
+	"Panamá":                   "Panama",
+	"Países Bajos":             "Netherlands",
+	"Polonia":                  "Poland",
+	"RD del Congo":             "DR Congo",
+	"República Centroafricana": "Central African Republic",
+	"República Checa":          "Czech Republic",
+	"República Dominicana":     "Dominican Republic",
+	"Rumania":                  "Romania",
+	"Rusia":                    "Russia",
+	"Sierra Leona":             "Sierra Leone",
+	"Siria":                    "Syria",
+	"Suecia":                   "Sweden",
+	"Suiza":                    "Switzerland",
+	"Surinam":                  "Suriname",
+	"Turquía":                  "Türkiye",
+	"Túnez":                    "Tunisia",
+	"Ucrania":                  "Ukraine",
+	"Zimbabue":                 "Zimbabwe",
+}
+
+// Canonical returns the English Transfermarkt spelling of a nationality.
+// Names that are already English, or unknown, are returned trimmed.
+func Canonical(name string) string {
+	name = strings.TrimSpace(name)
+	if en, ok := spanish[name]; ok {
+		return en
+	}
+	return name
+}
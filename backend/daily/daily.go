@@ -0,0 +1,173 @@
+// Package daily derives the daily challenge seeds and keeps past dailies on
+// disk. Seeds are an HMAC of the date under a server secret, so every user
+// gets the same challenge for a UTC date but nobody can compute tomorrow's.
+package daily
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DateLayout is the format of daily dates, in UTC.
+const DateLayout = "2006-01-02"
+
+var (
+	// ErrInvalidDate is returned for dates not in DateLayout.
+	ErrInvalidDate = errors.New("invalid date, want YYYY-MM-DD")
+	// ErrFuture is returned for dates after today (UTC).
+	ErrFuture = errors.New("daily not available yet")
+	// ErrNotFound is returned for past dates with no stored daily.
+	ErrNotFound = errors.New("no daily for this date")
+)
+
+// Today is the current UTC date.
+func Today() string {
+	return time.Now().UTC().Format(DateLayout)
+}
+
+// ParseDate validates a date and rejects days that haven't started in UTC.
+func ParseDate(date string) (string, error) {
+	t, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return "", ErrInvalidDate
+	}
+	if t.Format(DateLayout) > Today() {
+		return "", ErrFuture
+	}
+	return t.Format(DateLayout), nil
+}
+
+// Seed derives the seed for one part of a day's challenge ("questions",
+// "players", "bingo") so the parts don't share a random sequence.
+func Seed(secret []byte, date, part string) int64 {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(date + "/" + part))
+	sum := mac.Sum(nil)
+	return int64(binary.BigEndian.Uint64(sum[:8]) &^ (1 << 63))
+}
+
+// Store keeps one JSON file per date so a day's challenge doesn't change
+// when the scraped data or question library does.
+type Store struct {
+	dir string
+
+	mu    sync.Mutex
+	cache map[string][]byte
+}
+
+// NewStore returns a store writing to dir, creating it if needed.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, cache: map[string][]byte{}}, nil
+}
+
+// Secret returns the seed secret kept in the store's directory, creating
+// it on first use, so seeds survive restarts when no secret is configured.
+func (s *Store) Secret() ([]byte, error) {
+	path := filepath.Join(s.dir, ".secret")
+	b, err := os.ReadFile(path)
+	if err == nil && len(b) > 0 {
+		return b, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	b = make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	if err := writeAtomic(path, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Get loads the stored daily for date into v. It never builds one: past
+// dailies are only what players got that day, and ErrNotFound means none
+// was stored.
+func (s *Store) Get(date string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := s.read(date)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.decode(date, b, v)
+}
+
+// GetOrCreate loads the daily for date into v, or calls build, saves its
+// result and loads that. Calls for the same store are serialized so a day
+// is only built once. Only today's daily should be created this way.
+func (s *Store) GetOrCreate(date string, v any, build func() (any, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := s.read(date)
+	if errors.Is(err, os.ErrNotExist) {
+		built, err := build()
+		if err != nil {
+			return err
+		}
+		if b, err = json.MarshalIndent(built, "", "  "); err != nil {
+			return err
+		}
+		if err := writeAtomic(s.path(date), b); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	return s.decode(date, b, v)
+}
+
+func (s *Store) path(date string) string {
+	return filepath.Join(s.dir, date+".json")
+}
+
+// read returns the JSON for date from the cache or disk. s.mu must be held.
+func (s *Store) read(date string) ([]byte, error) {
+	if b, ok := s.cache[date]; ok {
+		return b, nil
+	}
+	return os.ReadFile(s.path(date))
+}
+
+// decode loads b into v and caches it once it parses. s.mu must be held.
+func (s *Store) decode(date string, b []byte, v any) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", s.path(date), err)
+	}
+	s.cache[date] = b
+	return nil
+}
+
+func writeAtomic(path string, b []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
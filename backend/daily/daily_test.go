@@ -0,0 +1,62 @@
+package daily
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreGetNeverBuilds(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]string
+	if err := s.Get("1999-01-01", &v); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing date = %v, want ErrNotFound", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1999-01-01.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Get wrote a daily: %v", err)
+	}
+
+	err = s.GetOrCreate("2024-05-01", &v, func() (any, error) {
+		return map[string]string{"date": "2024-05-01"}, nil
+	})
+	if err != nil || v["date"] != "2024-05-01" {
+		t.Fatalf("GetOrCreate = %v, %v", v, err)
+	}
+	v = nil
+	if err := s.Get("2024-05-01", &v); err != nil || v["date"] != "2024-05-01" {
+		t.Errorf("Get of a stored date = %v, %v", v, err)
+	}
+}
+
+func TestStoreSecretPersists(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.Secret()
+	if err != nil || len(a) == 0 {
+		t.Fatalf("Secret() = %x, %v", a, err)
+	}
+
+	reopened, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := reopened.Secret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("the secret changed after reopening the store")
+	}
+	if Seed(a, "2024-05-01", "bingo") != Seed(b, "2024-05-01", "bingo") {
+		t.Error("the same secret gave two seeds")
+	}
+}
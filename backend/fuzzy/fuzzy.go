@@ -0,0 +1,225 @@
+// Package fuzzy matches user-typed names against player names: it folds
+// accents and case, then ranks exact, prefix and typo-tolerant matches.
+// The player search and the quiz answer check share it so both accept the
+// same spellings.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// letters that don't decompose into a base letter plus a combining mark.
+var special = map[rune]string{
+	'ø': "o", 'Ø': "o",
+	'æ': "ae", 'Æ': "ae",
+	'œ': "oe", 'Œ': "oe",
+	'ß': "ss",
+	'đ': "d", 'Đ': "d",
+	'ł': "l", 'Ł': "l",
+	'ı': "i",
+	'þ': "th", 'Þ': "th",
+	'ð': "d", 'Ð': "d",
+}
+
+// Fold lowercases s, strips accents and collapses everything that isn't a
+// letter or digit into single spaces: "Christian Nørgaard" and
+// "christian  norgaard" both fold to "christian norgaard".
+func Fold(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if rep, ok := special[r]; ok {
+			b.WriteString(rep)
+			space = false
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+			space = false
+			continue
+		}
+		if !space && b.Len() > 0 {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), " ")
+}
+
+// Distance is the Levenshtein distance between a and b, in runes.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// Kind ranks how a name matched; lower is better.
+type Kind int
+
+const (
+	Exact  Kind = iota // the whole name, or a run of its words, equals the query
+	Prefix             // the name or one of its words starts with the query
+	Fuzzy              // within MaxDistance edits of the name or a run of its words
+	None
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Exact:
+		return "exact"
+	case Prefix:
+		return "prefix"
+	case Fuzzy:
+		return "fuzzy"
+	}
+	return "none"
+}
+
+// Result is the outcome of matching one name.
+type Result struct {
+	Kind     Kind
+	Distance int // edits for Fuzzy matches, 0 otherwise
+}
+
+// Better reports whether r ranks ahead of o.
+func (r Result) Better(o Result) bool {
+	if r.Kind != o.Kind {
+		return r.Kind < o.Kind
+	}
+	return r.Distance < o.Distance
+}
+
+// MaxDistance is the number of typos tolerated for a folded query: none
+// below four letters, then one per four letters, at most three.
+func MaxDistance(query string) int {
+	n := len([]rune(strings.ReplaceAll(query, " ", "")))
+	if n < 4 {
+		return 0
+	}
+	return min(n/4, 3)
+}
+
+// Match compares a query against a name. Both are folded first.
+func Match(query, name string) Result {
+	return MatchFolded(Fold(query), Fold(name))
+}
+
+// MatchFolded is Match for inputs already passed through Fold, to avoid
+// refolding the same names for every query.
+func MatchFolded(q, name string) Result {
+	if q == "" || name == "" {
+		return Result{Kind: None}
+	}
+	if q == name {
+		return Result{Kind: Exact}
+	}
+	words := strings.Fields(name)
+	qn := len(strings.Fields(q))
+
+	// Runs of as many consecutive words as the query has: "norgaard"
+	// against "christian norgaard" or "de bruyne" against "kevin de bruyne".
+	var runs []string
+	for i := 0; i+qn <= len(words); i++ {
+		runs = append(runs, strings.Join(words[i:i+qn], " "))
+	}
+	for _, run := range runs {
+		if run == q {
+			return Result{Kind: Exact}
+		}
+	}
+	if strings.HasPrefix(name, q) {
+		return Result{Kind: Prefix}
+	}
+	for i := range words {
+		if strings.HasPrefix(strings.Join(words[i:], " "), q) {
+			return Result{Kind: Prefix}
+		}
+	}
+
+	maxDist := MaxDistance(q)
+	if maxDist == 0 {
+		return Result{Kind: None}
+	}
+	best := Distance(q, name)
+	for _, run := range runs {
+		best = min(best, Distance(q, run))
+	}
+	if best <= maxDist {
+		return Result{Kind: Fuzzy, Distance: best}
+	}
+	return Result{Kind: None}
+}
+
+// SharedPrefix is the length, in runes, of the longest prefix a folded
+// query shares with a run of as many of name's words as it has. It breaks
+// ties between fuzzy matches at the same distance: "mbape" shares "mbap"
+// with "kylian mbappe" but only "mba" with "mbaye", and it's Mbappé the
+// user was typing.
+func SharedPrefix(q, name string) int {
+	qr := []rune(q)
+	words := strings.Fields(name)
+	qn := max(len(strings.Fields(q)), 1)
+	best := 0
+	for i := 0; i+qn <= len(words); i++ {
+		run := []rune(strings.Join(words[i:i+qn], " "))
+		n := 0
+		for n < len(qr) && n < len(run) && qr[n] == run[n] {
+			n++
+		}
+		best = max(best, n)
+	}
+	return best
+}
+
+// MatchAnswer checks a free-text guess against a quiz answer. Unlike Match
+// it never accepts prefixes, and a partial guess must be the trailing words
+// of the name, so "rooney" and "van persie" count but "wayne" or "van" don't.
+func MatchAnswer(guess, answer string) Result {
+	return MatchAnswerFolded(Fold(guess), Fold(answer))
+}
+
+// MatchAnswerFolded is MatchAnswer for inputs already passed through Fold.
+func MatchAnswerFolded(g, answer string) Result {
+	if g == "" || answer == "" {
+		return Result{Kind: None}
+	}
+	words := strings.Fields(answer)
+	best := Result{Kind: None}
+	maxDist := MaxDistance(g)
+	for i := range words {
+		tail := strings.Join(words[i:], " ")
+		if tail == g {
+			return Result{Kind: Exact}
+		}
+		if maxDist == 0 {
+			continue
+		}
+		if d := Distance(g, tail); d <= maxDist {
+			if r := (Result{Kind: Fuzzy, Distance: d}); r.Better(best) {
+				best = r
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,132 @@
+package fuzzy
+
+import "testing"
+
+// TestSharedPrefixBreaksTies covers the search tie-break: both names are
+// one edit from "mbape", and the one whose word starts like the query wins.
+func TestSharedPrefixBreaksTies(t *testing.T) {
+	q := Fold("mbape")
+	mbappe, mbaye := Fold("Kylian Mbappé"), Fold("Malick Mbaye")
+	if a, b := MatchFolded(q, mbappe), MatchFolded(q, mbaye); a != b {
+		t.Fatalf("expected a tie, got %+v and %+v", a, b)
+	}
+	if a, b := SharedPrefix(q, mbappe), SharedPrefix(q, mbaye); a <= b {
+		t.Errorf("SharedPrefix: mbappe %d, mbaye %d; want mbappe ahead", a, b)
+	}
+
+	tests := []struct {
+		q, name string
+		want    int
+	}{
+		{"mbape", "kylian mbappe", 4},
+		{"mbape", "malick mbaye", 3},
+		{"de bruine", "kevin de bruyne", 6},
+		{"xyz", "kylian mbappe", 0},
+		{"messi", "", 0},
+	}
+	for _, tt := range tests {
+		if got := SharedPrefix(tt.q, tt.name); got != tt.want {
+			t.Errorf("SharedPrefix(%q, %q) = %d, want %d", tt.q, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Christian Nørgaard", "christian norgaard"},
+		{"christian  norgaard", "christian norgaard"},
+		{"Kylian Mbappé", "kylian mbappe"},
+		{"  N'Golo Kanté!  ", "n golo kante"},
+		{"Łukasz Fabiański", "lukasz fabianski"},
+		{"İlkay Gündoğan", "ilkay gundogan"},
+		{"Martin Ødegaard", "martin odegaard"},
+		{"Strauß", "strauss"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMaxDistance(t *testing.T) {
+	tests := []struct {
+		q    string
+		want int
+	}{
+		{"", 0},
+		{"van", 0},
+		{"mesi", 1},
+		{"mbappe", 1},
+		{"ronaldoo", 2},
+		{"de bruyne", 2}, // spaces don't count
+		{"lewandowski", 2},
+		{"alexander arnold", 3},
+		{"pierre emerick aubameyang", 3},
+	}
+	for _, tt := range tests {
+		if got := MaxDistance(tt.q); got != tt.want {
+			t.Errorf("MaxDistance(%q) = %d, want %d", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		q, name string
+		want    Result
+	}{
+		{"Norgaard", "Christian Nørgaard", Result{Kind: Exact}},
+		{"christian norgaard", "Christian Nørgaard", Result{Kind: Exact}},
+		{"DE BRUYNE", "Kevin De Bruyne", Result{Kind: Exact}},
+		{"mbap", "Kylian Mbappé", Result{Kind: Prefix}},
+		{"kyl", "Kylian Mbappé", Result{Kind: Prefix}},
+		{"mbape", "Kylian Mbappé", Result{Kind: Fuzzy, Distance: 1}},
+		{"de bruine", "Kevin De Bruyne", Result{Kind: Fuzzy, Distance: 1}},
+		{"mesi", "Lionel Messi", Result{Kind: Fuzzy, Distance: 1}},
+		{"lewadoski", "Robert Lewandowski", Result{Kind: Fuzzy, Distance: 2}},
+		// past the threshold
+		{"msi", "Lionel Messi", Result{Kind: None}},
+		{"lewdoski", "Robert Lewandowski", Result{Kind: None}},
+		{"xabi", "Kylian Mbappé", Result{Kind: None}},
+		{"", "Lionel Messi", Result{Kind: None}},
+		{"messi", "", Result{Kind: None}},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.q, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %+v, want %+v", tt.q, tt.name, got, tt.want)
+		}
+		if got := MatchFolded(Fold(tt.q), Fold(tt.name)); got != tt.want {
+			t.Errorf("MatchFolded(%q, %q) = %+v, want %+v", tt.q, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAnswer(t *testing.T) {
+	tests := []struct {
+		guess, answer string
+		want          Result
+	}{
+		{"Norgaard", "Christian Nørgaard", Result{Kind: Exact}},
+		{"rooney", "Wayne Rooney", Result{Kind: Exact}},
+		{"van persie", "Robin van Persie", Result{Kind: Exact}},
+		{"Robin van Persie", "Robin van Persie", Result{Kind: Exact}},
+		{"mbape", "Kylian Mbappé", Result{Kind: Fuzzy, Distance: 1}},
+		{"kylian mbape", "Kylian Mbappé", Result{Kind: Fuzzy, Distance: 1}},
+		{"lewadoski", "Robert Lewandowski", Result{Kind: Fuzzy, Distance: 2}},
+		// first names, leading words and prefixes don't count
+		{"wayne", "Wayne Rooney", Result{Kind: None}},
+		{"van", "Robin van Persie", Result{Kind: None}},
+		{"mbap", "Kylian Mbappé", Result{Kind: None}},
+		// past the threshold
+		{"lewdoski", "Robert Lewandowski", Result{Kind: None}},
+		{"msi", "Lionel Messi", Result{Kind: None}},
+		{"", "Lionel Messi", Result{Kind: None}},
+	}
+	for _, tt := range tests {
+		if got := MatchAnswer(tt.guess, tt.answer); got != tt.want {
+			t.Errorf("MatchAnswer(%q, %q) = %+v, want %+v", tt.guess, tt.answer, got, tt.want)
+		}
+	}
+}
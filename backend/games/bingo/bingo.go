@@ -2,14 +2,17 @@ package bingo
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"path/filepath"
-	"sync"
-	"time"
+	"strconv"
+	"strings"
 )
 
+// ErrNotFound is returned when a board exists neither locally nor remotely.
+var ErrNotFound = errors.New("bingo board not found")
+
 type RemoteCategory struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
@@ -48,73 +51,61 @@ type Player struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	CategoryIDs []int  `json:"categoryIds"`
+	Position    string `json:"position,omitempty"`
+	Birthdate   string `json:"birthdate,omitempty"` // YYYY-MM-DD
 }
 
-var (
-	cacheMu sync.Mutex
-	cache   = map[int]struct {
-		fetchedAt  time.Time
-		categories []Category
-		players    []Player
-	}{}
-	cacheTTL = 30 * time.Minute
-)
-
-func FetchAndNormalize(id int) ([]Category, []Player, error) {
-	cacheMu.Lock()
-	entry, ok := cache[id]
-	if ok && time.Since(entry.fetchedAt) < cacheTTL {
-		cats := entry.categories
-		pls := entry.players
-		cacheMu.Unlock()
-		return cats, pls, nil
+// Satisfies reports whether the player fits the category.
+func (p Player) Satisfies(categoryID int) bool {
+	for _, id := range p.CategoryIDs {
+		if id == categoryID {
+			return true
+		}
 	}
-	cacheMu.Unlock()
+	return false
+}
 
-	candidates := []string{
-		filepath.Join("data", "remote_bingo"),
-		filepath.Join("cmd", "scrape_bingo", "data", "remote_bingo"),
-		filepath.Join("..", "cmd", "scrape_bingo", "data", "remote_bingo"),
-		filepath.Join("..", "..", "cmd", "scrape_bingo", "data", "remote_bingo"),
+// Board is a normalized bingo board. Each cell lists the category IDs a
+// player must satisfy to be placed there; Players is the order in which the
+// game deals them.
+type Board struct {
+	ID         int        `json:"id"`
+	Cells      [][]int    `json:"cells"`
+	Categories []Category `json:"categories"`
+	Players    []Player   `json:"players"`
+}
+
+// LoadBoardFile reads a downloaded board from path without touching any cache.
+// The board ID is taken from the file name (e.g. 720.json).
+func LoadBoardFile(path string) (Board, error) {
+	id, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(path), ".json"))
+	if err != nil {
+		return Board{}, fmt.Errorf("invalid board file name %s", path)
 	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Board{}, err
+	}
+	return parseBoard(id, b)
+}
 
+func parseBoard(id int, b []byte) (Board, error) {
 	var root remoteRoot
-	foundLocal := false
-	for _, d := range candidates {
-		p := filepath.Join(d, fmt.Sprintf("%d.json", id))
-		if b, err := os.ReadFile(p); err == nil {
-			if err := json.Unmarshal(b, &root); err != nil {
-				return nil, nil, fmt.Errorf("failed to parse local file %s: %w", p, err)
-			}
-			foundLocal = true
-			break
-		}
+	if err := json.Unmarshal(b, &root); err != nil {
+		return Board{}, fmt.Errorf("failed to parse board %d: %w", id, err)
 	}
+	return normalize(id, root), nil
+}
 
-	if !foundLocal {
-		// fallback to remote fetch
-		url := fmt.Sprintf("https://playfootball.games/api/football-bingo/%d.json", id)
-		resp, err := http.Get(url)
-		if err != nil {
-			return nil, nil, err
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return nil, nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-		}
-
-		if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
-			return nil, nil, err
-		}
-	}
+func normalize(id int, root remoteRoot) Board {
+	board := Board{ID: id}
 
-	// flatten remit to categories
-	var cats []Category
+	// flatten remit to categories, remembering which ones share a cell
 	for _, row := range root.GameData.Remit {
+		cell := make([]int, 0, len(row))
 		for _, rc := range row {
 			img := fmt.Sprintf("https://playfootball.games/media/categories/%d.webp", rc.ID)
-			cats = append(cats, Category{
+			board.Categories = append(board.Categories, Category{
 				ID:          rc.ID,
 				Name:        rc.Name,
 				DisplayName: rc.DisplayName,
@@ -122,10 +113,11 @@ func FetchAndNormalize(id int) ([]Category, []Player, error) {
 				Image:       img,
 				HelperText:  rc.HelperText,
 			})
+			cell = append(cell, rc.ID)
 		}
+		board.Cells = append(board.Cells, cell)
 	}
 
-	var players []Player
 	for _, rp := range root.GameData.Players {
 		full := rp.G
 		if full != "" && rp.F != "" {
@@ -133,16 +125,8 @@ func FetchAndNormalize(id int) ([]Category, []Player, error) {
 		} else if full == "" {
 			full = rp.F
 		}
-		players = append(players, Player{ID: rp.ID, Name: full, CategoryIDs: rp.V})
+		board.Players = append(board.Players, Player{ID: rp.ID, Name: full, CategoryIDs: rp.V})
 	}
 
-	cacheMu.Lock()
-	cache[id] = struct {
-		fetchedAt  time.Time
-		categories []Category
-		players    []Player
-	}{time.Now(), cats, players}
-	cacheMu.Unlock()
-
-	return cats, players, nil
+	return board
 }
@@ -0,0 +1,310 @@
+package bingo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type box2boxRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type box2boxCategory struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Reputation  int    `json:"reputation"`
+	Type        int    `json:"type"`
+	DisplayName string `json:"displayName"`
+	HelperText  string `json:"helperText,omitempty"`
+}
+
+type box2boxGameRoot struct {
+	GameData struct {
+		Game                        string `json:"game"`
+		CountryCount                int    `json:"countryCount"`
+		ForcedTeamMinimumReputation int    `json:"forcedTeamMinimumReputation"`
+		CategoryOverrides           struct {
+			Include []box2boxRef `json:"include"`
+			Exclude []box2boxRef `json:"exclude"`
+		} `json:"categoryOverrides"`
+		ExcludedCombosAutomated [][]box2boxRef    `json:"excludedCombosAutomated"`
+		ExcludedCombosManual    [][]box2boxRef    `json:"excludedCombosManual"`
+		Categories              []box2boxCategory `json:"categories"`
+	} `json:"gameData"`
+}
+
+// box2boxPlayer is the compact record used by players.json: n is the name,
+// v the category IDs, p the position and a the birthdate (DD/MM/YYYY).
+type box2boxPlayer struct {
+	N string `json:"n"`
+	V []int  `json:"v"`
+	P string `json:"p,omitempty"`
+	A string `json:"a,omitempty"`
+}
+
+// Box2Box is a box2box game definition (dataGame.json) together with its
+// player database (players.json).
+type Box2Box struct {
+	Game         string
+	CountryCount int
+	// ForcedTeamMinimumReputation is the reputation at least one club on
+	// every board must reach.
+	ForcedTeamMinimumReputation int
+	Categories                  []Category
+	Reputation                  map[int]int
+	Include                     []int
+	Exclude                     []int
+	// ExcludedCombos lists category pairs the game never puts in one cell.
+	// Generated boards use single-category cells, so they never apply there.
+	ExcludedCombos [][]int
+	// Players have no IDs in the source file; they are numbered from 1 in
+	// file order.
+	Players []Player
+}
+
+// LoadBox2Box parses dataGame.json and players.json from dir.
+func LoadBox2Box(dir string) (*Box2Box, error) {
+	var game box2boxGameRoot
+	if err := readJSON(filepath.Join(dir, "dataGame.json"), &game); err != nil {
+		return nil, err
+	}
+	var players struct {
+		Players []box2boxPlayer `json:"players"`
+	}
+	if err := readJSON(filepath.Join(dir, "players.json"), &players); err != nil {
+		return nil, err
+	}
+
+	gd := game.GameData
+	out := &Box2Box{
+		Game:                        gd.Game,
+		CountryCount:                gd.CountryCount,
+		ForcedTeamMinimumReputation: gd.ForcedTeamMinimumReputation,
+		Reputation:                  map[int]int{},
+	}
+	for _, c := range gd.Categories {
+		out.Categories = append(out.Categories, Category{
+			ID:          c.ID,
+			Name:        c.Name,
+			DisplayName: c.DisplayName,
+			Type:        c.Type,
+			Image:       fmt.Sprintf("https://playfootball.games/media/categories/%d.webp", c.ID),
+			HelperText:  c.HelperText,
+		})
+		out.Reputation[c.ID] = c.Reputation
+	}
+	for _, r := range gd.CategoryOverrides.Include {
+		out.Include = append(out.Include, r.ID)
+	}
+	for _, r := range gd.CategoryOverrides.Exclude {
+		out.Exclude = append(out.Exclude, r.ID)
+	}
+	for _, combos := range [][][]box2boxRef{gd.ExcludedCombosAutomated, gd.ExcludedCombosManual} {
+		for _, combo := range combos {
+			ids := make([]int, 0, len(combo))
+			for _, r := range combo {
+				ids = append(ids, r.ID)
+			}
+			out.ExcludedCombos = append(out.ExcludedCombos, ids)
+		}
+	}
+
+	for i, bp := range players.Players {
+		out.Players = append(out.Players, Player{
+			ID:          i + 1,
+			Name:        strings.TrimSpace(bp.N),
+			CategoryIDs: bp.V,
+			Position:    bp.P,
+			Birthdate:   isoDate(bp.A),
+		})
+	}
+	out.disambiguate()
+	return out, nil
+}
+
+// disambiguate tells apart players who share a name, like the three
+// "Alemao": their most reputable club goes after the name, then the birth
+// year and the position for those who still read the same, and a number
+// as a last resort.
+func (g *Box2Box) disambiguate() {
+	clubs := map[int]string{}
+	for _, c := range g.Categories {
+		if c.Type == TypeClub {
+			clubs[c.ID] = c.Name
+		}
+	}
+	topClub := func(p Player) string {
+		best := -1
+		for _, id := range p.CategoryIDs {
+			if _, ok := clubs[id]; !ok {
+				continue
+			}
+			if best == -1 || g.Reputation[id] > g.Reputation[best] || g.Reputation[id] == g.Reputation[best] && id < best {
+				best = id
+			}
+		}
+		return clubs[best]
+	}
+	birthYear := func(p Player) string {
+		if len(p.Birthdate) < 4 {
+			return ""
+		}
+		return p.Birthdate[:4]
+	}
+	position := func(p Player) string { return p.Position }
+
+	byName := map[string][]int{}
+	for i, p := range g.Players {
+		byName[p.Name] = append(byName[p.Name], i)
+	}
+	for _, idx := range byName {
+		if len(idx) < 2 {
+			continue
+		}
+		labels := make([][]string, len(idx))
+		collides := func(j int) bool {
+			for k := range labels {
+				if k != j && strings.Join(labels[k], ", ") == strings.Join(labels[j], ", ") {
+					return true
+				}
+			}
+			return false
+		}
+		for _, part := range []func(Player) string{topClub, birthYear, position} {
+			var clash []int
+			for j := range idx {
+				if labels[j] == nil || collides(j) {
+					clash = append(clash, j)
+				}
+			}
+			for _, j := range clash {
+				if v := part(g.Players[idx[j]]); v != "" {
+					labels[j] = append(labels[j], v)
+				}
+			}
+		}
+		var clash []int
+		for j := range idx {
+			if collides(j) {
+				clash = append(clash, j)
+			}
+		}
+		for n, j := range clash {
+			labels[j] = append(labels[j], fmt.Sprintf("#%d", n+1))
+		}
+		for j, pi := range idx {
+			if len(labels[j]) > 0 {
+				g.Players[pi].Name += " (" + strings.Join(labels[j], ", ") + ")"
+			}
+		}
+	}
+}
+
+func readJSON(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// isoDate converts DD/MM/YYYY to YYYY-MM-DD, returning "" when it can't.
+func isoDate(s string) string {
+	t, err := time.Parse("02/01/2006", strings.TrimSpace(s))
+	if err != nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// box2boxMix mirrors the spread of a box2box board: mostly clubs and
+// countries, plus trophies and the "misc" achievements.
+var box2boxMix = []mixEntry{
+	{TypeClub, 5},
+	{TypeNationality, 4},
+	{6, 3}, // trophies
+	{8, 3}, // misc: positions, finals, decade of birth
+	{TypeLeague, 1},
+}
+
+// Generate builds a solvable board following the game's category rules:
+// every club not excluded plus the override include list, countries limited
+// to the CountryCount most reputable, and at least one club of
+// ForcedTeamMinimumReputation or more.
+func (g *Box2Box) Generate(opts GenerateOptions) (Board, error) {
+	opts.defaults()
+
+	excluded := map[int]bool{}
+	for _, id := range g.Exclude {
+		excluded[id] = true
+	}
+	included := map[int]bool{}
+	for _, id := range g.Include {
+		included[id] = true
+	}
+
+	var countries []Category
+	allowed := map[int]Category{}
+	for _, c := range g.Categories {
+		if excluded[c.ID] {
+			continue
+		}
+		switch {
+		case c.Type == TypeClub:
+			allowed[c.ID] = c
+		case included[c.ID] && c.Type == TypeNationality:
+			countries = append(countries, c)
+		case included[c.ID]:
+			allowed[c.ID] = c
+		}
+	}
+	sort.SliceStable(countries, func(i, j int) bool {
+		return g.Reputation[countries[i].ID] > g.Reputation[countries[j].ID]
+	})
+	if g.CountryCount > 0 && len(countries) > g.CountryCount {
+		countries = countries[:g.CountryCount]
+	}
+	for _, c := range countries {
+		allowed[c.ID] = c
+	}
+
+	members := map[int][]int{}
+	for pi, p := range g.Players {
+		for _, id := range p.CategoryIDs {
+			if _, ok := allowed[id]; ok {
+				members[id] = append(members[id], pi)
+			}
+		}
+	}
+
+	p := pool{byType: map[int][]candidate{}, mix: box2boxMix}
+	ids := make([]int, 0, len(allowed))
+	for id := range allowed {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if len(members[id]) < opts.MinCategorySize {
+			continue
+		}
+		c := candidate{cat: allowed[id], members: members[id]}
+		p.byType[c.cat.Type] = append(p.byType[c.cat.Type], c)
+		if c.cat.Type == TypeClub && g.Reputation[id] >= g.ForcedTeamMinimumReputation {
+			p.require = append(p.require, c)
+		}
+	}
+	for _, pl := range g.Players {
+		pl.CategoryIDs = nil
+		p.players = append(p.players, pl)
+	}
+	return buildBoard(p, opts)
+}
@@ -0,0 +1,151 @@
+package bingo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeBox2Box writes a small box2box game to a temp dir:
+//   - clubs 100-109, with 101 excluded and only 100 reaching the forced
+//     reputation of 80;
+//   - countries 1-8, of which 1-6 are included and only the 3 most
+//     reputable may be used;
+//   - trophies 200-202 with 202 left out of the include list, misc
+//     categories 300-303 and league 400.
+func writeBox2Box(t *testing.T, players []map[string]any) string {
+	t.Helper()
+	type ref struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	var cats []map[string]any
+	var include, exclude []ref
+	add := func(id, typ, rep int, name string, included bool) {
+		cats = append(cats, map[string]any{"id": id, "name": name, "type": typ, "reputation": rep, "displayName": name})
+		if included {
+			include = append(include, ref{id, name})
+		}
+	}
+	for id := 100; id <= 109; id++ {
+		rep := 50
+		if id == 100 {
+			rep = 90
+		}
+		add(id, TypeClub, rep, fmt.Sprintf("Club %d", id), false)
+	}
+	exclude = append(exclude, ref{101, "Club 101"})
+	for id := 1; id <= 8; id++ {
+		add(id, TypeNationality, 100-id*10, fmt.Sprintf("Country %d", id), id <= 6)
+	}
+	for id := 200; id <= 202; id++ {
+		add(id, 6, 50, fmt.Sprintf("Trophy %d", id), id != 202)
+	}
+	for id := 300; id <= 303; id++ {
+		add(id, 8, 50, fmt.Sprintf("Misc %d", id), true)
+	}
+	add(400, TypeLeague, 50, "League", true)
+
+	game := map[string]any{"gameData": map[string]any{
+		"game":                        "test",
+		"countryCount":                3,
+		"forcedTeamMinimumReputation": 80,
+		"categoryOverrides":           map[string]any{"include": include, "exclude": exclude},
+		"categories":                  cats,
+	}}
+	if players == nil {
+		for i := 0; i < 200; i++ {
+			v := []int{100 + i%10, 1 + i%8, 200 + i%3, 300 + i%4}
+			if i%2 == 0 {
+				v = append(v, 400)
+			}
+			players = append(players, map[string]any{"n": fmt.Sprintf("Player %d", i), "v": v})
+		}
+	}
+
+	dir := t.TempDir()
+	for name, v := range map[string]any{"dataGame.json": game, "players.json": map[string]any{"players": players}} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBox2BoxGenerateRules(t *testing.T) {
+	g, err := LoadBox2Box(writeBox2Box(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := map[int]bool{400: true}
+	for id := 100; id <= 109; id++ {
+		allowed[id] = id != 101
+	}
+	for id := 1; id <= 3; id++ {
+		allowed[id] = true
+	}
+	allowed[200], allowed[201] = true, true
+	for id := 300; id <= 303; id++ {
+		allowed[id] = true
+	}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		board, err := g.Generate(GenerateOptions{Seed: seed})
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		forced := false
+		for _, c := range board.Categories {
+			if !allowed[c.ID] {
+				t.Errorf("seed %d: category %d (%s) is excluded, not included or past the country count", seed, c.ID, c.Name)
+			}
+			if c.Type == TypeClub && g.Reputation[c.ID] >= g.ForcedTeamMinimumReputation {
+				forced = true
+			}
+		}
+		if !forced {
+			t.Errorf("seed %d: no club with reputation %d or more", seed, g.ForcedTeamMinimumReputation)
+		}
+		if !Solve(board).Solvable {
+			t.Errorf("seed %d: board not solvable", seed)
+		}
+	}
+}
+
+func TestBox2BoxDuplicateNames(t *testing.T) {
+	players := []map[string]any{
+		{"n": "Alemao", "v": []int{100, 102}, "a": "01/03/1975"},
+		{"n": "Alemao", "v": []int{100}, "a": "22/11/1961"},
+		{"n": "Alemao", "v": []int{103}},
+		{"n": "Alex", "v": []int{104}, "p": "CB"},
+		{"n": "Alex", "v": []int{104}, "p": "CAM"},
+		{"n": "Rafael", "v": []int{105}},
+		{"n": "Rafael", "v": []int{105}},
+		{"n": "Kaka", "v": []int{100}},
+	}
+	g, err := LoadBox2Box(writeBox2Box(t, players))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Alemao (Club 100, 1975)",
+		"Alemao (Club 100, 1961)",
+		"Alemao (Club 103)",
+		"Alex (Club 104, CB)",
+		"Alex (Club 104, CAM)",
+		"Rafael (Club 105, #1)",
+		"Rafael (Club 105, #2)",
+		"Kaka",
+	}
+	for i, p := range g.Players {
+		if p.Name != want[i] {
+			t.Errorf("player %d: name %q, want %q", i+1, p.Name, want[i])
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package bingo
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Category types used by generated boards. Nationality, club and league
+// reuse the playfootball.games numbering.
+const (
+	TypeNationality = 1
+	TypeClub        = 2
+	TypeLeague      = 3
+	TypeAge         = 10
+	TypeMarketValue = 11
+	TypeContract    = 12
+)
+
+// GenerateOptions controls Generate. Zero fields take the defaults of a
+// downloaded board: 16 cells and 42 dealt players.
+type GenerateOptions struct {
+	Seed    int64
+	Cells   int
+	Players int
+	// MinCategorySize drops categories with fewer matching players.
+	MinCategorySize int
+}
+
+// ErrNoBoard is returned when no solvable board can be built from the roster.
+var ErrNoBoard = errors.New("could not generate a solvable bingo board")
+
+type ageBracket struct {
+	name     string
+	min, max int
+}
+
+var ageBrackets = []ageBracket{
+	{"Under 21", 0, 20},
+	{"Aged 21-24", 21, 24},
+	{"Aged 25-29", 25, 29},
+	{"Aged 30+", 30, 99},
+}
+
+type valueBracket struct {
+	name     string
+	min, max int64
+}
+
+var valueBrackets = []valueBracket{
+	{"Valued under €5m", 1, 4_999_999},
+	{"Valued €5m-€20m", 5_000_000, 19_999_999},
+	{"Valued €20m-€50m", 20_000_000, 49_999_999},
+	{"Valued €50m+", 50_000_000, 1 << 62},
+}
+
+type mixEntry struct {
+	typ   int
+	count int
+}
+
+// cellMix is how many cells of each type a roster board aims for; types
+// that run short are backfilled from the others.
+var cellMix = []mixEntry{
+	{TypeNationality, 4},
+	{TypeClub, 4},
+	{TypeLeague, 2},
+	{TypeAge, 2},
+	{TypeMarketValue, 2},
+	{TypeContract, 2},
+}
+
+type candidate struct {
+	cat     Category
+	members []int // indexes into pool.players
+}
+
+// pool is the input shared by every board generator: the players that can be
+// dealt and the categories they fall into.
+type pool struct {
+	players []Player // only ID, Name and metadata; CategoryIDs are filled per board
+	byType  map[int][]candidate
+	mix     []mixEntry
+	// require, when set, holds categories of which at least one must be on
+	// every board.
+	require []candidate
+}
+
+func (o *GenerateOptions) defaults() {
+	if o.Cells <= 0 {
+		o.Cells = 16
+	}
+	if o.Players <= 0 {
+		o.Players = 42
+	}
+	if o.MinCategorySize <= 0 {
+		o.MinCategorySize = 3
+	}
+	if o.Players < o.Cells {
+		o.Players = o.Cells
+	}
+}
+
+// Generate builds an original board from scraped rosters. The same roster
+// and seed always produce the same board, and the returned board is always
+// solvable: a matching player for every cell is dealt before the decoys are
+// mixed in.
+func Generate(roster []RosterPlayer, opts GenerateOptions) (Board, error) {
+	opts.defaults()
+	p := pool{byType: rosterCategories(roster, opts.MinCategorySize), mix: cellMix}
+	for _, rp := range roster {
+		p.players = append(p.players, Player{ID: rp.ID, Name: rp.Name})
+	}
+	return buildBoard(p, opts)
+}
+
+func buildBoard(p pool, opts GenerateOptions) (Board, error) {
+	if len(p.players) < opts.Players {
+		return Board{}, fmt.Errorf("%w: pool has %d players, need %d", ErrNoBoard, len(p.players), opts.Players)
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	for attempt := 0; attempt < 20; attempt++ {
+		cells := pickCells(rng, p, opts.Cells)
+		if len(cells) < opts.Cells {
+			break
+		}
+
+		// Solve the cells against the whole pool in random order to find
+		// one distinct player per cell.
+		order := rng.Perm(len(p.players))
+		all := Board{Cells: make([][]int, len(cells))}
+		for i, c := range cells {
+			all.Cells[i] = []int{c.cat.ID}
+		}
+		member := make([]map[int]bool, len(cells))
+		for i, c := range cells {
+			member[i] = map[int]bool{}
+			for _, pi := range c.members {
+				member[i][pi] = true
+			}
+		}
+		for _, pi := range order {
+			all.Players = append(all.Players, boardPlayer(p.players[pi], cells, member, pi))
+		}
+		rep := Solve(all)
+		if !rep.Solvable {
+			continue
+		}
+
+		used := map[int]bool{}
+		var dealt []int
+		for _, ai := range rep.Assignment {
+			pi := order[ai]
+			used[pi] = true
+			dealt = append(dealt, pi)
+		}
+		for _, pi := range order {
+			if len(dealt) == opts.Players {
+				break
+			}
+			if !used[pi] {
+				used[pi] = true
+				dealt = append(dealt, pi)
+			}
+		}
+		rng.Shuffle(len(dealt), func(i, j int) { dealt[i], dealt[j] = dealt[j], dealt[i] })
+
+		board := Board{Cells: all.Cells}
+		for _, c := range cells {
+			board.Categories = append(board.Categories, c.cat)
+		}
+		for _, pi := range dealt {
+			board.Players = append(board.Players, boardPlayer(p.players[pi], cells, member, pi))
+		}
+		if Solve(board).Solvable {
+			return board, nil
+		}
+	}
+	return Board{}, ErrNoBoard
+}
+
+func boardPlayer(base Player, cells []candidate, member []map[int]bool, pi int) Player {
+	base.CategoryIDs = []int{}
+	for i, c := range cells {
+		if member[i][pi] {
+			base.CategoryIDs = append(base.CategoryIDs, c.cat.ID)
+		}
+	}
+	return base
+}
+
+// pickCells draws distinct categories following the pool's mix, starting
+// with one of the required categories when the pool has any.
+func pickCells(rng *rand.Rand, p pool, n int) []candidate {
+	taken := map[int]bool{}
+	var cells []candidate
+	take := func(from []candidate, count int) {
+		for _, i := range rng.Perm(len(from)) {
+			if count <= 0 || len(cells) == n {
+				return
+			}
+			if taken[from[i].cat.ID] {
+				continue
+			}
+			taken[from[i].cat.ID] = true
+			cells = append(cells, from[i])
+			count--
+		}
+	}
+	if len(p.require) > 0 {
+		take(p.require, 1)
+	}
+	for _, m := range p.mix {
+		already := 0
+		for _, c := range cells {
+			if c.cat.Type == m.typ {
+				already++
+			}
+		}
+		take(p.byType[m.typ], m.count-already)
+	}
+	for _, m := range p.mix {
+		take(p.byType[m.typ], n)
+	}
+	rng.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	return cells
+}
+
+// rosterCategories groups the roster into candidate categories by type.
+// Category IDs are assigned in sorted key order so they are stable for a
+// given roster, regardless of the seed.
+func rosterCategories(roster []RosterPlayer, minSize int) map[int][]candidate {
+	type key struct {
+		typ  int
+		name string
+	}
+	groups := map[key]*candidate{}
+	add := func(typ int, name, display, image string, ri int) {
+		k := key{typ, name}
+		g, ok := groups[k]
+		if !ok {
+			g = &candidate{cat: Category{Name: name, DisplayName: display, Type: typ}}
+			groups[k] = g
+		}
+		if g.cat.Image == "" {
+			g.cat.Image = image
+		}
+		if n := len(g.members); n == 0 || g.members[n-1] != ri {
+			g.members = append(g.members, ri)
+		}
+	}
+
+	for ri, p := range roster {
+		for i, n := range p.Nationalities {
+			// The flag belongs to the first nationality only; a second
+			// country takes its image from a player born to it.
+			flag := ""
+			if i == 0 {
+				flag = p.FlagURL
+			}
+			add(TypeNationality, n, n, flag, ri)
+		}
+		if p.Club != "" {
+			add(TypeClub, p.Club, p.Club, "", ri)
+		}
+		if p.LeagueName != "" {
+			add(TypeLeague, p.LeagueName, p.LeagueName, "", ri)
+		}
+		for _, b := range ageBrackets {
+			if p.Age > 0 && p.Age >= b.min && p.Age <= b.max {
+				add(TypeAge, b.name, b.name, "", ri)
+			}
+		}
+		for _, b := range valueBrackets {
+			if p.MarketValue >= b.min && p.MarketValue <= b.max {
+				add(TypeMarketValue, b.name, b.name, "", ri)
+			}
+		}
+		if p.ContractYear > 0 {
+			name := fmt.Sprintf("Contract until %d", p.ContractYear)
+			add(TypeContract, name, name, "", ri)
+		}
+	}
+
+	keys := make([]key, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].typ != keys[j].typ {
+			return keys[i].typ < keys[j].typ
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	byType := map[int][]candidate{}
+	for i, k := range keys {
+		g := groups[k]
+		g.cat.ID = i + 1
+		if len(g.members) >= minSize {
+			byType[k.typ] = append(byType[k.typ], *g)
+		}
+	}
+	return byType
+}
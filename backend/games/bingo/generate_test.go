@@ -0,0 +1,181 @@
+package bingo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// testRoster is 8 clubs of 8 players spread over two leagues, with enough
+// overlap in nationality, age, value and contract for every cell type.
+func testRoster() []RosterPlayer {
+	nations := []string{"Argentina", "Brazil", "France", "Spain", "England", "Portugal"}
+	var roster []RosterPlayer
+	for club := 0; club < 8; club++ {
+		league, leagueName := "premier", "Premier League"
+		if club%2 == 1 {
+			league, leagueName = "laliga", "LaLiga"
+		}
+		for i := 0; i < 8; i++ {
+			n := len(roster)
+			p := RosterPlayer{
+				ID:            1000 + n,
+				Name:          fmt.Sprintf("Player %d", n),
+				Club:          fmt.Sprintf("Club %d", club),
+				League:        league,
+				LeagueName:    leagueName,
+				Nationalities: []string{nations[n%len(nations)]},
+				FlagURL:       fmt.Sprintf("https://flags.test/%d.png", n%len(nations)),
+				Age:           18 + n%17,
+				MarketValue:   int64(n%9) * 8_000_000,
+				ContractYear:  2026 + n%4,
+			}
+			if n%5 == 0 {
+				p.Nationalities = append(p.Nationalities, nations[(n+1)%len(nations)])
+			}
+			roster = append(roster, p)
+		}
+	}
+	return roster
+}
+
+func TestGenerateSolvable(t *testing.T) {
+	roster := testRoster()
+	for seed := int64(1); seed <= 25; seed++ {
+		board, err := Generate(roster, GenerateOptions{Seed: seed})
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if len(board.Cells) != 16 || len(board.Categories) != 16 {
+			t.Errorf("seed %d: %d cells and %d categories, want 16", seed, len(board.Cells), len(board.Categories))
+		}
+		if len(board.Players) != 42 {
+			t.Errorf("seed %d: %d players, want 42", seed, len(board.Players))
+		}
+		seen := map[int]bool{}
+		for _, p := range board.Players {
+			if seen[p.ID] {
+				t.Errorf("seed %d: player %d dealt twice", seed, p.ID)
+			}
+			seen[p.ID] = true
+		}
+		if rep := Solve(board); !rep.Solvable {
+			t.Errorf("seed %d: board not solvable, covers %d/%d, dead cells %v", seed, rep.Covered, rep.Cells, rep.DeadCells)
+		}
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	roster := testRoster()
+	a, err := Generate(roster, GenerateOptions{Seed: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Generate(roster, GenerateOptions{Seed: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the same seed produced two different boards")
+	}
+
+	c, err := Generate(roster, GenerateOptions{Seed: 43})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(a, c) {
+		t.Error("seeds 42 and 43 produced the same board")
+	}
+}
+
+func TestGenerateTooFewPlayers(t *testing.T) {
+	if _, err := Generate(testRoster()[:10], GenerateOptions{Seed: 1}); err == nil {
+		t.Error("a 10 player roster generated a 42 player board")
+	}
+}
+
+// TestNationalityImage checks that a dual national's flag stays on their
+// first country: the second one takes the flag of a player born to it.
+func TestNationalityImage(t *testing.T) {
+	roster := []RosterPlayer{
+		{ID: 1, Name: "Dual", Nationalities: []string{"Spain", "Argentina"}, FlagURL: "es.png"},
+		{ID: 2, Name: "Argentine", Nationalities: []string{"Argentina"}, FlagURL: "ar.png"},
+		{ID: 3, Name: "Other dual", Nationalities: []string{"Spain", "Morocco"}, FlagURL: "es.png"},
+	}
+	images := map[string]string{}
+	for _, c := range rosterCategories(roster, 1)[TypeNationality] {
+		images[c.cat.Name] = c.cat.Image
+	}
+	want := map[string]string{"Spain": "es.png", "Argentina": "ar.png", "Morocco": ""}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("nationality images = %v, want %v", images, want)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	players := func(cats ...[]int) []Player {
+		var out []Player
+		for i, c := range cats {
+			out = append(out, Player{ID: i + 1, CategoryIDs: c})
+		}
+		return out
+	}
+	tests := []struct {
+		name       string
+		board      Board
+		solvable   bool
+		covered    int
+		minPlayers int
+		dead       []int
+	}{
+		{
+			// The first player fits both cells, so the matching has to
+			// move them to make room for the second.
+			name:       "augmenting path",
+			board:      Board{Cells: [][]int{{1}, {2}}, Players: players([]int{1, 2}, []int{1})},
+			solvable:   true,
+			covered:    2,
+			minPlayers: 2,
+			dead:       []int{},
+		},
+		{
+			name:       "combined cell needs every category",
+			board:      Board{Cells: [][]int{{1, 2}, {3}}, Players: players([]int{1}, []int{3}, []int{1, 2})},
+			solvable:   true,
+			covered:    2,
+			minPlayers: 3,
+			dead:       []int{},
+		},
+		{
+			name:     "dead cell",
+			board:    Board{Cells: [][]int{{1}, {9}}, Players: players([]int{1}, []int{1})},
+			solvable: false,
+			covered:  1,
+			dead:     []int{1},
+		},
+		{
+			name:     "one player can't fill two cells",
+			board:    Board{Cells: [][]int{{1}, {2}}, Players: players([]int{1, 2})},
+			solvable: false,
+			covered:  1,
+			dead:     []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := Solve(tt.board)
+			if rep.Solvable != tt.solvable || rep.Covered != tt.covered || rep.MinPlayers != tt.minPlayers {
+				t.Errorf("solvable %v, covered %d, min players %d; want %v, %d, %d",
+					rep.Solvable, rep.Covered, rep.MinPlayers, tt.solvable, tt.covered, tt.minPlayers)
+			}
+			if !reflect.DeepEqual(rep.DeadCells, tt.dead) {
+				t.Errorf("dead cells = %v, want %v", rep.DeadCells, tt.dead)
+			}
+			for ci, pi := range rep.Assignment {
+				if pi >= 0 && !tt.board.Players[pi].FitsCell(tt.board.Cells[ci]) {
+					t.Errorf("player %d assigned to cell %d they don't fit", pi, ci)
+				}
+			}
+		})
+	}
+}
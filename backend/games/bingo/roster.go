@@ -0,0 +1,86 @@
+package bingo
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"futbol912.com/countries"
+	"futbol912.com/transfermarkt"
+)
+
+// RosterSource is the league a team roster belongs to.
+type RosterSource struct {
+	League string // API key, e.g. "premier"
+	Name   string // display name, e.g. "Premier League"
+}
+
+// RosterPlayer is a scraped player with the fields the generator builds
+// categories from. Zero values mean the field was missing or unparseable.
+type RosterPlayer struct {
+	ID            int
+	Name          string
+	Club          string
+	League        string
+	LeagueName    string
+	Nationalities []string
+	FlagURL       string // flag of the first nationality
+	Age           int
+	MarketValue   int64 // euros
+	ContractYear  int
+}
+
+// TeamRoster converts one team file's players, skipping those without a
+// numeric ID or a name.
+func TeamRoster(src RosterSource, team, file string, players []transfermarkt.Player) []RosterPlayer {
+	club := clubName(team, file)
+	var out []RosterPlayer
+	for _, p := range players {
+		id, err := strconv.Atoi(p.ID)
+		if err != nil || strings.TrimSpace(p.Name) == "" {
+			continue
+		}
+		// Files scraped before the typed fields existed only have the raw
+		// strings.
+		p.Normalize()
+		rp := RosterPlayer{
+			ID:          id,
+			Name:        strings.TrimSpace(p.Name),
+			Club:        club,
+			League:      src.League,
+			LeagueName:  src.Name,
+			FlagURL:     p.FlagURL,
+			Age:         p.AgeYears,
+			MarketValue: p.MarketValueEUR,
+		}
+		if len(p.ContractExpires) >= 4 {
+			rp.ContractYear, _ = strconv.Atoi(p.ContractExpires[:4])
+		}
+		for i, n := range p.Nationalities {
+			n = countries.Canonical(n)
+			if n == "" {
+				if i == 0 {
+					// The flag is the first nationality's, which was dropped.
+					rp.FlagURL = ""
+				}
+				continue
+			}
+			rp.Nationalities = append(rp.Nationalities, n)
+		}
+		out = append(out, rp)
+	}
+	return out
+}
+
+// clubName turns a team slug like "fc-arsenal" into "Fc Arsenal".
+func clubName(team, file string) string {
+	slug := strings.TrimSpace(team)
+	if slug == "" {
+		slug = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	words := strings.FieldsFunc(slug, func(r rune) bool { return r == '-' || r == '_' || r == ' ' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,92 @@
+package bingo
+
+// Report describes whether a board's player sequence can cover every cell.
+type Report struct {
+	BoardID int `json:"boardId"`
+	Cells   int `json:"cells"`
+	// Covered is the largest number of cells that can be filled when every
+	// player in the sequence is placed at most once.
+	Covered  int  `json:"covered"`
+	Solvable bool `json:"solvable"`
+	// MinPlayers is the length of the shortest prefix of the sequence that
+	// can fill the whole board, or 0 when the board is unsolvable.
+	MinPlayers int `json:"minPlayers"`
+	// DeadCells holds the indexes of cells no player satisfies.
+	DeadCells []int `json:"deadCells"`
+	// Assignment maps each cell index to the index of the player placed
+	// there, or -1 when the cell stays empty.
+	Assignment []int `json:"assignment"`
+}
+
+// FitsCell reports whether the player satisfies every category of the cell.
+func (p Player) FitsCell(cell []int) bool {
+	if len(cell) == 0 {
+		return false
+	}
+	for _, id := range cell {
+		if !p.Satisfies(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// Solve matches the board's players to its cells, each player filling at
+// most one cell. Players are added in sequence order and the matching is
+// augmented after each one, so the first prefix that fills the board is
+// also the minimum number of players needed.
+func Solve(b Board) Report {
+	rep := Report{BoardID: b.ID, Cells: len(b.Cells), DeadCells: []int{}}
+
+	fits := make([][]int, len(b.Players))
+	alive := make([]bool, len(b.Cells))
+	for pi, p := range b.Players {
+		for ci, cell := range b.Cells {
+			if p.FitsCell(cell) {
+				fits[pi] = append(fits[pi], ci)
+				alive[ci] = true
+			}
+		}
+	}
+	for ci, ok := range alive {
+		if !ok {
+			rep.DeadCells = append(rep.DeadCells, ci)
+		}
+	}
+
+	cellOwner := make([]int, len(b.Cells))
+	for i := range cellOwner {
+		cellOwner[i] = -1
+	}
+
+	var augment func(pi int, seen []bool) bool
+	augment = func(pi int, seen []bool) bool {
+		for _, ci := range fits[pi] {
+			if seen[ci] {
+				continue
+			}
+			seen[ci] = true
+			if cellOwner[ci] == -1 || augment(cellOwner[ci], seen) {
+				cellOwner[ci] = pi
+				return true
+			}
+		}
+		return false
+	}
+
+	for pi := range b.Players {
+		if rep.Covered == len(b.Cells) {
+			break
+		}
+		if augment(pi, make([]bool, len(b.Cells))) {
+			rep.Covered++
+			if rep.Covered == len(b.Cells) {
+				rep.MinPlayers = pi + 1
+			}
+		}
+	}
+
+	rep.Solvable = len(b.Cells) > 0 && rep.Covered == len(b.Cells)
+	rep.Assignment = cellOwner
+	return rep
+}
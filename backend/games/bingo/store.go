@@ -0,0 +1,174 @@
+package bingo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultBaseURL is where playfootball.games serves bingo boards.
+const DefaultBaseURL = "https://playfootball.games/api/football-bingo"
+
+// Fetcher retrieves the raw JSON of a board that is not on disk. It returns
+// ErrNotFound when the board does not exist.
+type Fetcher interface {
+	Fetch(ctx context.Context, id int) ([]byte, error)
+}
+
+// HTTPFetcher downloads boards from BaseURL/<id>.json.
+type HTTPFetcher struct {
+	Client  *http.Client
+	BaseURL string // defaults to DefaultBaseURL
+}
+
+// Fetch implements Fetcher.
+func (f HTTPFetcher) Fetch(ctx context.Context, id int) ([]byte, error) {
+	base := f.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	url := fmt.Sprintf("%s/%d.json", strings.TrimRight(base, "/"), id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Store serves boards from a data directory of downloaded <id>.json files,
+// falling back to its Fetcher for boards that are not on disk. Loaded boards
+// are cached for the TTL, and concurrent misses for the same ID share a
+// single load.
+type Store struct {
+	dir string
+	ttl time.Duration
+	// Fetcher is used for boards missing from dir. A nil Fetcher keeps the
+	// store offline. Set it before the store is first used.
+	Fetcher Fetcher
+
+	mu    sync.Mutex
+	cache map[int]cacheEntry
+	group singleflight.Group
+}
+
+type cacheEntry struct {
+	fetchedAt time.Time
+	board     Board
+}
+
+// NewStore returns a store reading boards from dir. When client is nil the
+// store never goes to the network.
+func NewStore(dir string, client *http.Client, ttl time.Duration) *Store {
+	s := &Store{dir: dir, ttl: ttl, cache: map[int]cacheEntry{}}
+	if client != nil {
+		s.Fetcher = HTTPFetcher{Client: client}
+	}
+	return s
+}
+
+// IDs returns the sorted IDs of the boards available on disk. A missing
+// data directory just means no boards were downloaded.
+func (s *Store) IDs() ([]int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids := []int{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// Board returns board id, keeping its cell layout.
+func (s *Store) Board(ctx context.Context, id int) (Board, error) {
+	s.mu.Lock()
+	entry, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < s.ttl {
+		return entry.board, nil
+	}
+
+	// The load is shared by every waiter, so it runs detached from the
+	// context of whichever request started it; a cancelled caller stops
+	// waiting without failing the others.
+	loadCtx := context.WithoutCancel(ctx)
+	ch := s.group.DoChan(strconv.Itoa(id), func() (any, error) {
+		board, err := s.load(loadCtx, id)
+		if err != nil {
+			return Board{}, err
+		}
+		s.mu.Lock()
+		s.cache[id] = cacheEntry{fetchedAt: time.Now(), board: board}
+		s.mu.Unlock()
+		return board, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return Board{}, res.Err
+		}
+		return res.Val.(Board), nil
+	case <-ctx.Done():
+		return Board{}, ctx.Err()
+	}
+}
+
+func (s *Store) load(ctx context.Context, id int) (Board, error) {
+	path := filepath.Join(s.dir, fmt.Sprintf("%d.json", id))
+	board, err := LoadBoardFile(path)
+	if err == nil {
+		return board, nil
+	}
+	if !os.IsNotExist(err) {
+		return Board{}, err
+	}
+
+	if s.Fetcher == nil {
+		return Board{}, ErrNotFound
+	}
+	b, err := s.Fetcher.Fetch(ctx, id)
+	if err != nil {
+		return Board{}, err
+	}
+	return parseBoard(id, b)
+}
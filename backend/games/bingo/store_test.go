@@ -0,0 +1,168 @@
+package bingo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sampleBoard is a downloaded board, served by the test upstream under any
+// ID.
+func sampleBoard(t *testing.T) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("..", "..", "cmd", "scrape_bingo", "data", "remote_bingo", "720.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// upstream stands in for playfootball.games: it serves body for 721.json,
+// 404s everything else and counts requests. A non-nil gate holds every
+// response until it is closed.
+func upstream(t *testing.T, body []byte, gate chan struct{}) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if gate != nil {
+			<-gate
+		}
+		if r.URL.Path != "/721.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func remoteStore(t *testing.T, srv *httptest.Server) *Store {
+	s := NewStore(t.TempDir(), nil, time.Minute)
+	s.Fetcher = HTTPFetcher{Client: srv.Client(), BaseURL: srv.URL}
+	return s
+}
+
+func TestStoreLocalHit(t *testing.T) {
+	body := sampleBoard(t)
+	srv, hits := upstream(t, body, nil)
+	s := remoteStore(t, srv)
+	if err := os.WriteFile(filepath.Join(s.dir, "720.json"), body, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := s.Board(context.Background(), 720)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.ID != 720 || len(board.Cells) == 0 || len(board.Players) == 0 {
+		t.Errorf("got board %d with %d cells and %d players", board.ID, len(board.Cells), len(board.Players))
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("a board on disk made %d upstream requests", n)
+	}
+	ids, err := s.IDs()
+	if err != nil || len(ids) != 1 || ids[0] != 720 {
+		t.Errorf("IDs() = %v, %v; want [720]", ids, err)
+	}
+}
+
+func TestStoreRemoteFallback(t *testing.T) {
+	srv, hits := upstream(t, sampleBoard(t), nil)
+	s := remoteStore(t, srv)
+
+	for i := 0; i < 2; i++ {
+		board, err := s.Board(context.Background(), 721)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if board.ID != 721 || len(board.Players) == 0 {
+			t.Errorf("got board %d with %d players", board.ID, len(board.Players))
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("%d upstream requests, want 1 (the second read is cached)", n)
+	}
+}
+
+func TestStoreRemoteNotFound(t *testing.T) {
+	srv, _ := upstream(t, sampleBoard(t), nil)
+	s := remoteStore(t, srv)
+	if _, err := s.Board(context.Background(), 999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStoreOffline(t *testing.T) {
+	s := NewStore(t.TempDir(), nil, time.Minute)
+	if s.Fetcher != nil {
+		t.Fatal("a nil client gave the store a fetcher")
+	}
+	if _, err := s.Board(context.Background(), 721); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+
+	missing := NewStore(filepath.Join(t.TempDir(), "missing"), nil, time.Minute)
+	ids, err := missing.IDs()
+	if err != nil || len(ids) != 0 {
+		t.Errorf("IDs() of a missing dir = %v, %v; want an empty list", ids, err)
+	}
+}
+
+// TestStoreSingleflight sends concurrent misses for one board and cancels
+// the first caller: there must be a single upstream request, and the other
+// callers must still get the board.
+func TestStoreSingleflight(t *testing.T) {
+	gate := make(chan struct{})
+	srv, hits := upstream(t, sampleBoard(t), gate)
+	s := remoteStore(t, srv)
+
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := s.Board(firstCtx, 721)
+		firstErr <- err
+	}()
+	for hits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	const waiters = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Board(context.Background(), 721)
+			errs <- err
+		}()
+	}
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got %v, want context.Canceled", err)
+	}
+	// Give the waiters time to join the load before it completes.
+	time.Sleep(20 * time.Millisecond)
+	close(gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("waiter failed: %v", err)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("%d upstream requests, want 1", n)
+	}
+}
@@ -0,0 +1,235 @@
+// Package httpcache is an on-disk cache of GET responses for the scrapers.
+// Each URL's last 200 response is stored with its ETag and Last-Modified
+// and revalidated with a conditional request, so an unchanged page costs a
+// 304 instead of a full download. Offline, only the cache is used, which
+// lets parsers be reworked against pages already on disk.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotCached is returned offline for a URL the cache doesn't have.
+var ErrNotCached = errors.New("not in the http cache")
+
+// Cache is an http.RoundTripper that serves GETs from Dir. A nil *Cache
+// passes every request through.
+type Cache struct {
+	Dir string
+	// MaxAge is how long a stored response is used without asking the
+	// server. 0 revalidates every time.
+	MaxAge time.Duration
+	// Offline never touches the network: any stored response is served,
+	// whatever its age, and the rest fail with ErrNotCached.
+	Offline bool
+	// Transport makes the actual requests; nil means
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// entry is one cached response, stored as JSON in Dir.
+type entry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Fetched      time.Time `json:"fetched"` // when the body was downloaded
+	Checked      time.Time `json:"checked"` // last download or 304
+	Body         []byte    `json:"body"`
+}
+
+// Open returns a cache in dir, creating it if needed.
+func Open(dir string, maxAge time.Duration, offline bool) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Cache{Dir: dir, MaxAge: maxAge, Offline: offline}, nil
+}
+
+// Client returns an http.Client going through the cache.
+func (c *Cache) Client(timeout time.Duration) *http.Client {
+	if c == nil {
+		return &http.Client{Timeout: timeout}
+	}
+	return &http.Client{Transport: c, Timeout: timeout}
+}
+
+// Fresh reports whether a GET of url would be answered from disk without
+// a request, so callers can skip their rate limiting.
+func (c *Cache) Fresh(url string) bool {
+	if c == nil {
+		return false
+	}
+	e, err := c.load(url)
+	if err != nil || e == nil {
+		return false
+	}
+	return c.Offline || c.fresh(e, time.Now())
+}
+
+func (c *Cache) fresh(e *entry, now time.Time) bool {
+	return c.MaxAge > 0 && now.Sub(e.Checked) < c.MaxAge
+}
+
+// RoundTrip implements http.RoundTripper. Only GETs are cached, and only
+// 200 responses are stored; other statuses reach the caller untouched so
+// its retry logic still sees them.
+func (c *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c == nil || req.Method != http.MethodGet {
+		return c.transport().RoundTrip(req)
+	}
+	url := req.URL.String()
+	e, err := c.load(url)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if c.Offline {
+		if e == nil {
+			return nil, ErrNotCached
+		}
+		return e.response(req), nil
+	}
+	if e != nil && c.fresh(e, now) {
+		return e.response(req), nil
+	}
+
+	if e != nil {
+		req = req.Clone(req.Context())
+		if e.ETag != "" {
+			req.Header.Set("If-None-Match", e.ETag)
+		}
+		if e.LastModified != "" {
+			req.Header.Set("If-Modified-Since", e.LastModified)
+		}
+	}
+	resp, err := c.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && e != nil:
+		resp.Body.Close()
+		e.Checked = now
+		if v := resp.Header.Get("ETag"); v != "" {
+			e.ETag = v
+		}
+		if v := resp.Header.Get("Last-Modified"); v != "" {
+			e.LastModified = v
+		}
+		if err := c.store(e); err != nil {
+			return nil, err
+		}
+		return e.response(req), nil
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		e = &entry{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			ContentType:  resp.Header.Get("Content-Type"),
+			Fetched:      now,
+			Checked:      now,
+			Body:         body,
+		}
+		if err := c.store(e); err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+	return resp, nil
+}
+
+func (c *Cache) transport() http.RoundTripper {
+	if c == nil || c.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.Transport
+}
+
+// path is the entry file for url: the SHA-256 of the URL, so any URL makes
+// a valid file name.
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the stored entry for url, or nil if there is none. An
+// unreadable entry counts as missing and gets replaced on the next fetch.
+func (c *Cache) load(url string) (*entry, error) {
+	b, err := os.ReadFile(c.path(url))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e entry
+	if err := json.Unmarshal(b, &e); err != nil || e.URL != url {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// store writes e atomically, so concurrent scrapers never read half an
+// entry.
+func (c *Cache) store(e *entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(e.URL))
+}
+
+// response rebuilds a 200 for req from the entry.
+func (e *entry) response(req *http.Request) *http.Response {
+	h := http.Header{}
+	if e.ContentType != "" {
+		h.Set("Content-Type", e.ContentType)
+	}
+	if e.ETag != "" {
+		h.Set("ETag", e.ETag)
+	}
+	if e.LastModified != "" {
+		h.Set("Last-Modified", e.LastModified)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
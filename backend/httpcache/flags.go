@@ -0,0 +1,50 @@
+package httpcache
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Flags are the cache options shared by every scraper command.
+type Flags struct {
+	Dir     string
+	MaxAge  time.Duration
+	Offline bool
+}
+
+// DefaultDir is $HTTP_CACHE_DIR, or futbol912/http under the user's cache
+// directory.
+func DefaultDir() string {
+	if dir := os.Getenv("HTTP_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(base, "futbol912", "http")
+}
+
+// RegisterFlags adds -cache, -max-age and -offline to fs.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Dir, "cache", DefaultDir(), `HTTP cache directory ("" disables the cache; default $HTTP_CACHE_DIR)`)
+	fs.DurationVar(&f.MaxAge, "max-age", 0, "use cached pages younger than this without asking the server (0 = always revalidate)")
+	fs.BoolVar(&f.Offline, "offline", false, "only serve pages from the HTTP cache, never touch the network")
+	return f
+}
+
+// Open opens the cache the flags describe. It returns a nil *Cache, which
+// passes requests through, when -cache is empty.
+func (f *Flags) Open() (*Cache, error) {
+	if f.Dir == "" {
+		if f.Offline {
+			return nil, errors.New("-offline needs a -cache directory")
+		}
+		return nil, nil
+	}
+	return Open(f.Dir, f.MaxAge, f.Offline)
+}
@@ -0,0 +1,221 @@
+// Package leaderboard ranks game results per mode on an all-time board and
+// on one board per UTC day and ISO week. Each player keeps only their best
+// entry on a board; players are identified by an anonymous token the
+// client generates, which is never shown on the boards.
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Period selects which board of a mode to read.
+type Period string
+
+const (
+	Global Period = "global"
+	Daily  Period = "daily"
+	Weekly Period = "weekly"
+)
+
+// Periods lists every period; a submission lands on one board of each.
+var Periods = []Period{Global, Daily, Weekly}
+
+var (
+	// ErrUnknownPeriod is returned for periods not in Periods.
+	ErrUnknownPeriod = errors.New("unknown period, want global, daily or weekly")
+	// ErrInvalidPlayer is returned for malformed player tokens.
+	ErrInvalidPlayer = errors.New("invalid player token")
+	// ErrAlreadySubmitted is returned when a session's result was already
+	// submitted, under any player.
+	ErrAlreadySubmitted = errors.New("result already submitted")
+	// ErrTooOld is returned for results that finished more than
+	// MaxResultAge ago.
+	ErrTooOld = errors.New("result too old to submit")
+)
+
+// MaxResultAge is how long after a game its result can be submitted: a
+// week, the longest board period besides the all-time one. Stores only
+// need to remember a session's claim that long.
+const MaxResultAge = 7 * 24 * time.Hour
+
+// ParsePeriod validates a period name; "" means Global.
+func ParsePeriod(s string) (Period, error) {
+	if s == "" {
+		return Global, nil
+	}
+	for _, p := range Periods {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", ErrUnknownPeriod
+}
+
+// Key names the board of a period that t falls in: "all", a date
+// (2006-01-02) or an ISO week (2006-W01), all in UTC.
+func Key(p Period, t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case Daily:
+		return t.Format("2006-01-02")
+	case Weekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return "all"
+}
+
+// Board is the store key of a mode's board for a period key.
+func Board(mode string, p Period, key string) string {
+	return mode + "/" + string(p) + "/" + key
+}
+
+// ValidPlayer reports whether token looks like a client-generated player
+// token: 8 to 64 letters, digits, dashes or underscores.
+func ValidPlayer(token string) bool {
+	if len(token) < 8 || len(token) > 64 {
+		return false
+	}
+	for _, r := range token {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// maxNameLen caps display names, in runes.
+const maxNameLen = 24
+
+// DefaultName is shown for players who didn't pick a name.
+const DefaultName = "Anónimo"
+
+// CleanName trims a display name and cuts it to maxNameLen runes.
+func CleanName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if r := []rune(name); len(r) > maxNameLen {
+		name = string(r[:maxNameLen])
+	}
+	if name == "" {
+		return DefaultName
+	}
+	return name
+}
+
+// Entry is a player's best result on a board.
+type Entry struct {
+	Player  string    `json:"player"`
+	Name    string    `json:"name"`
+	Score   int       `json:"score"`
+	Session string    `json:"session"`
+	At      time.Time `json:"at"` // when the game finished
+}
+
+// beats reports whether e ranks above o: higher score, then earlier.
+func (e Entry) beats(o Entry) bool {
+	if e.Score != o.Score {
+		return e.Score > o.Score
+	}
+	return e.At.Before(o.At)
+}
+
+// Ranked is an entry with its 1-based position on the board.
+type Ranked struct {
+	Rank int `json:"rank"`
+	Entry
+}
+
+// Leaderboards ranks entries kept in a Store.
+type Leaderboards struct {
+	store Store
+	now   func() time.Time
+}
+
+// New returns leaderboards over store.
+func New(store Store) *Leaderboards {
+	return &Leaderboards{store: store, now: time.Now}
+}
+
+// Submit puts e on the mode's global board and on the daily and weekly
+// boards of e.At, and returns the player's rank on each. Each session can
+// only be submitted once, within MaxResultAge of e.At.
+func (l *Leaderboards) Submit(mode string, e Entry) (map[Period]int, error) {
+	if !ValidPlayer(e.Player) {
+		return nil, ErrInvalidPlayer
+	}
+	e.Name = CleanName(e.Name)
+	e.At = e.At.UTC()
+	if e.At.Before(l.now().Add(-MaxResultAge)) {
+		return nil, ErrTooOld
+	}
+	fresh, err := l.store.Claim(e.Session, e.At)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, ErrAlreadySubmitted
+	}
+
+	ranks := map[Period]int{}
+	for _, p := range Periods {
+		board := Board(mode, p, Key(p, e.At))
+		if err := l.store.Put(board, e); err != nil {
+			return nil, err
+		}
+		me, ok, err := l.Rank(board, e.Player)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ranks[p] = me.Rank
+		}
+	}
+	return ranks, nil
+}
+
+// Page returns limit entries of a board starting at offset, and the total
+// number of entries on it.
+func (l *Leaderboards) Page(board string, offset, limit int) ([]Ranked, int, error) {
+	all, err := l.sorted(board)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := []Ranked{}
+	for i := offset; i < len(all) && i < offset+limit; i++ {
+		out = append(out, Ranked{Rank: i + 1, Entry: all[i]})
+	}
+	return out, len(all), nil
+}
+
+// Rank returns the player's entry on a board, if any.
+func (l *Leaderboards) Rank(board, player string) (Ranked, bool, error) {
+	all, err := l.sorted(board)
+	if err != nil {
+		return Ranked{}, false, err
+	}
+	for i, e := range all {
+		if e.Player == player {
+			return Ranked{Rank: i + 1, Entry: e}, true, nil
+		}
+	}
+	return Ranked{}, false, nil
+}
+
+func (l *Leaderboards) sorted(board string) ([]Entry, error) {
+	all, err := l.store.Entries(board)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.Score != b.Score || !a.At.Equal(b.At) {
+			return a.beats(b)
+		}
+		return a.Player < b.Player
+	})
+	return all, nil
+}
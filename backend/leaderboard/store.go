@@ -0,0 +1,254 @@
+package leaderboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Store keeps boards. Implementations must be safe for concurrent use.
+type Store interface {
+	// Claim marks a session whose game finished at at as submitted,
+	// reporting false if it already was. Claims may be forgotten once at
+	// is more than MaxResultAge ago, when Submit turns the session away
+	// anyway.
+	Claim(session string, at time.Time) (bool, error)
+	// Put keeps e as the player's entry on board unless their stored
+	// entry ranks at least as high.
+	Put(board string, e Entry) error
+	// Entries returns a copy of every entry on board, in any order.
+	Entries(board string) ([]Entry, error)
+}
+
+// MemoryStore keeps boards in memory; they are lost on restart.
+type MemoryStore struct {
+	mu     sync.Mutex
+	claims map[string]time.Time        // session -> when its game finished
+	boards map[string]map[string]Entry // board -> player -> entry
+}
+
+// NewMemoryStore returns an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{claims: map[string]time.Time{}, boards: map[string]map[string]Entry{}}
+}
+
+func (st *MemoryStore) Claim(session string, at time.Time) (bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.claim(session, at), nil
+}
+
+func (st *MemoryStore) Put(board string, e Entry) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.put(board, e)
+	return nil
+}
+
+func (st *MemoryStore) Entries(board string) ([]Entry, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	out := make([]Entry, 0, len(st.boards[board]))
+	for _, e := range st.boards[board] {
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (st *MemoryStore) claim(session string, at time.Time) bool {
+	if _, ok := st.claims[session]; ok {
+		return false
+	}
+	st.claims[session] = at
+	return true
+}
+
+// pruneClaims forgets the claims of games that finished before cutoff.
+func (st *MemoryStore) pruneClaims(cutoff time.Time) {
+	for s, at := range st.claims {
+		if at.Before(cutoff) {
+			delete(st.claims, s)
+		}
+	}
+}
+
+// put reports whether the board changed.
+func (st *MemoryStore) put(board string, e Entry) bool {
+	b := st.boards[board]
+	if b == nil {
+		b = map[string]Entry{}
+		st.boards[board] = b
+	}
+	if old, ok := b[e.Player]; ok && !e.beats(old) {
+		return false
+	}
+	b[e.Player] = e
+	return true
+}
+
+// logRecord is one line of a FileStore's log.
+type logRecord struct {
+	Claim string     `json:"claim,omitempty"`
+	At    *time.Time `json:"at,omitempty"` // when the claimed game finished
+	Board string     `json:"board,omitempty"`
+	Entry *Entry     `json:"entry,omitempty"`
+}
+
+// FileStore is a MemoryStore backed by an append-only JSON-lines log, so
+// boards survive restarts on a single instance. The log is compacted to
+// the current state when the store is opened, dropping the claims older
+// than MaxResultAge.
+type FileStore struct {
+	mem  *MemoryStore
+	path string
+
+	mu sync.Mutex // guards f and keeps log order equal to apply order
+	f  *os.File
+}
+
+// OpenFileStore replays the log at path, creating it if missing.
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	st := &FileStore{mem: NewMemoryStore(), path: path}
+	if err := st.replay(); err != nil {
+		return nil, err
+	}
+	if err := st.compact(time.Now()); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	st.f = f
+	return st, nil
+}
+
+func (st *FileStore) replay() error {
+	f, err := os.Open(st.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		var rec logRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			// A crash can leave a torn last line; anything else is
+			// corruption worth stopping for.
+			if !sc.Scan() {
+				break
+			}
+			return fmt.Errorf("%s:%d: %w", st.path, line, err)
+		}
+		if rec.Claim != "" {
+			// Claims logged without a time are kept for one more
+			// MaxResultAge from now.
+			at := time.Now()
+			if rec.At != nil {
+				at = *rec.At
+			}
+			st.mem.claim(rec.Claim, at)
+		}
+		if rec.Entry != nil {
+			st.mem.put(rec.Board, *rec.Entry)
+		}
+	}
+	return sc.Err()
+}
+
+// compact rewrites the log with one record per entry and per claim still
+// within MaxResultAge of now.
+func (st *FileStore) compact(now time.Time) error {
+	st.mem.pruneClaims(now.Add(-MaxResultAge))
+
+	tmp, err := os.CreateTemp(filepath.Dir(st.path), ".leaderboard-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for s, at := range st.mem.claims {
+		if err := enc.Encode(logRecord{Claim: s, At: &at}); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	for board, b := range st.mem.boards {
+		for _, e := range b {
+			e := e
+			if err := enc.Encode(logRecord{Board: board, Entry: &e}); err != nil {
+				tmp.Close()
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), st.path)
+}
+
+func (st *FileStore) Claim(session string, at time.Time) (bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.mem.mu.Lock()
+	fresh := st.mem.claim(session, at)
+	st.mem.mu.Unlock()
+	if !fresh {
+		return false, nil
+	}
+	return true, st.append(logRecord{Claim: session, At: &at})
+}
+
+func (st *FileStore) Put(board string, e Entry) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.mem.mu.Lock()
+	changed := st.mem.put(board, e)
+	st.mem.mu.Unlock()
+	if !changed {
+		return nil
+	}
+	return st.append(logRecord{Board: board, Entry: &e})
+}
+
+func (st *FileStore) Entries(board string) ([]Entry, error) {
+	return st.mem.Entries(board)
+}
+
+func (st *FileStore) append(rec logRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := st.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return st.f.Sync()
+}
+
+// Close closes the log.
+func (st *FileStore) Close() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.f.Close()
+}
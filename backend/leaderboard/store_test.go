@@ -0,0 +1,210 @@
+package leaderboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openStore(t *testing.T, path string) *FileStore {
+	t.Helper()
+	st, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+// logLines reads the store's log, one record per line.
+func logLines(t *testing.T, path string) []logRecord {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var out []logRecord
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec logRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("log line %q: %v", sc.Text(), err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestFileStoreReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boards.jsonl")
+	at := time.Now().UTC().Truncate(time.Second)
+	st := openStore(t, path)
+	lb := New(st)
+	if _, err := lb.Submit("age", Entry{Player: "player-one", Name: "Uno", Score: 40, Session: "s1", At: at}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lb.Submit("age", Entry{Player: "player-one", Score: 70, Session: "s2", At: at.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lb.Submit("age", Entry{Player: "player-two", Score: 50, Session: "s3", At: at}); err != nil {
+		t.Fatal(err)
+	}
+	st.Close()
+
+	// Reopening replays the log: the best entry per player survives and
+	// the sessions stay claimed.
+	lb = New(openStore(t, path))
+	page, total, err := lb.Page(Board("age", Global, "all"), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || page[0].Player != "player-one" || page[0].Score != 70 || page[1].Score != 50 {
+		t.Errorf("replayed board = %+v (total %d), want player-one 70 then player-two 50", page, total)
+	}
+	if _, err := lb.Submit("age", Entry{Player: "player-three", Score: 90, Session: "s1", At: at}); !errors.Is(err, ErrAlreadySubmitted) {
+		t.Errorf("resubmitting a replayed session: err = %v, want ErrAlreadySubmitted", err)
+	}
+}
+
+func TestFileStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boards.jsonl")
+	now := time.Now().UTC().Truncate(time.Second)
+	st := openStore(t, path)
+	for i, score := range []int{10, 30, 20} {
+		e := Entry{Player: "player-one", Score: score, Session: "s" + strconv.Itoa(i+1), At: now}
+		if _, err := st.Claim(e.Session, e.At); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.Put("age/global/all", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A claim from before MaxResultAge, and one logged before claims had
+	// a time.
+	if _, err := st.Claim("old", now.Add(-MaxResultAge-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.append(logRecord{Claim: "untimed"}); err != nil {
+		t.Fatal(err)
+	}
+	st.Close()
+	if n := len(logLines(t, path)); n != 7 {
+		t.Fatalf("log has %d lines before compaction, want 7", n)
+	}
+
+	st = openStore(t, path)
+	claims := map[string]bool{}
+	entries := 0
+	for _, rec := range logLines(t, path) {
+		if rec.Claim != "" {
+			claims[rec.Claim] = true
+			if rec.At == nil {
+				t.Errorf("compacted claim %q has no time", rec.Claim)
+			}
+		}
+		if rec.Entry != nil {
+			entries++
+			if rec.Entry.Score != 30 {
+				t.Errorf("compacted entry = %+v, want the best score 30", rec.Entry)
+			}
+		}
+	}
+	if entries != 1 {
+		t.Errorf("compacted log has %d entries, want 1", entries)
+	}
+	for _, s := range []string{"s1", "s2", "s3", "untimed"} {
+		if !claims[s] {
+			t.Errorf("compaction dropped the claim of %s", s)
+		}
+	}
+	if claims["old"] {
+		t.Error("compaction kept a claim older than MaxResultAge")
+	}
+	if fresh, err := st.Claim("s2", now); err != nil || fresh {
+		t.Errorf("Claim(s2) after compaction = %v, %v; want already claimed", fresh, err)
+	}
+}
+
+func TestFileStoreTornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boards.jsonl")
+	st := openStore(t, path)
+	e := Entry{Player: "player-one", Score: 40, Session: "s1", At: time.Now().UTC()}
+	if err := st.Put("age/global/all", e); err != nil {
+		t.Fatal(err)
+	}
+	st.Close()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"board":"age/global/all","entry":{"player":"player-two","sco`)
+	f.Close()
+
+	st = openStore(t, path)
+	got, err := st.Entries("age/global/all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Player != "player-one" {
+		t.Errorf("entries = %+v, want only player-one", got)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "player-two") {
+		t.Error("compaction kept the torn line")
+	}
+}
+
+func TestFileStoreCorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boards.jsonl")
+	if err := os.WriteFile(path, []byte("{not json}\n{\"claim\":\"s1\"}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileStore(path); err == nil {
+		t.Error("OpenFileStore accepted a corrupt line before the last one")
+	}
+}
+
+func TestSubmitDuplicateSession(t *testing.T) {
+	lb := New(NewMemoryStore())
+	at := time.Now()
+	if _, err := lb.Submit("bingo", Entry{Player: "player-one", Score: 40, Session: "s1", At: at}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := lb.Submit("bingo", Entry{Player: "player-two", Score: 40, Session: "s1", At: at})
+	if !errors.Is(err, ErrAlreadySubmitted) {
+		t.Errorf("same session under another player: err = %v, want ErrAlreadySubmitted", err)
+	}
+	if _, total, _ := lb.Page(Board("bingo", Global, "all"), 0, 10); total != 1 {
+		t.Errorf("board has %d entries, want 1", total)
+	}
+}
+
+func TestSubmitTooOld(t *testing.T) {
+	lb := New(NewMemoryStore())
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	lb.now = func() time.Time { return now }
+
+	old := Entry{Player: "player-one", Score: 40, Session: "s1", At: now.Add(-MaxResultAge - time.Second)}
+	if _, err := lb.Submit("age", old); !errors.Is(err, ErrTooOld) {
+		t.Errorf("result older than MaxResultAge: err = %v, want ErrTooOld", err)
+	}
+	recent := Entry{Player: "player-one", Score: 40, Session: "s2", At: now.Add(-MaxResultAge + time.Second)}
+	ranks, err := lb.Submit("age", recent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranks[Global] != 1 || ranks[Daily] != 1 || ranks[Weekly] != 1 {
+		t.Errorf("ranks = %v, want first on every board", ranks)
+	}
+}
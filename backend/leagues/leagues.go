@@ -0,0 +1,154 @@
+// Package leagues loads the league registry (leagues.json) shared by the
+// scrapers and the API. Adding a competition is a new entry in that file.
+package leagues
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileName is the registry's file name at the backend root.
+const FileName = "leagues.json"
+
+// League is one competition in the registry.
+type League struct {
+	Key     string `json:"key"`     // API key, e.g. "premier"
+	Name    string `json:"name"`    // display name, e.g. "Premier League"
+	Country string `json:"country"` // e.g. "Inglaterra"
+	Code    string `json:"code"`    // Transfermarkt competition code, e.g. "GB1"
+	Season  int    `json:"season"`  // default saison_id, the year the season starts
+	Host    string `json:"host"`    // e.g. "www.transfermarkt.com"
+	Path    string `json:"path"`    // competition slug, e.g. "premier-league"
+	// Dir holds one subdirectory of team JSONs per season, relative to the
+	// backend root.
+	Dir string `json:"dir"`
+	// MaxAttempts is the number of GETs per page before giving up; 0 means
+	// the scraper default.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// FileNames picks how team files are named: "" uses the club's URL
+	// slug, FileNamesLinkText its link text on the competition page.
+	FileNames string `json:"file_names,omitempty"`
+}
+
+// FileNamesLinkText names team files from the club's link text, as the
+// original Bundesliga and Ligue 1 scrapers did ("augsburgo.json").
+const FileNamesLinkText = "link-text"
+
+// Registry is the parsed leagues.json, in file order.
+type Registry struct {
+	Leagues []League `json:"leagues"`
+	// Root is the directory holding the file; League.Dir is relative to it.
+	Root string `json:"-"`
+}
+
+// Load reads and validates a registry file.
+func Load(path string) (*Registry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var reg Registry
+	if err := json.Unmarshal(b, &reg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	seen := map[string]bool{}
+	for i, l := range reg.Leagues {
+		if l.Key == "" || l.Code == "" || l.Host == "" || l.Path == "" || l.Dir == "" {
+			return nil, fmt.Errorf("%s: league %d needs key, code, host, path and dir", path, i)
+		}
+		if l.FileNames != "" && l.FileNames != FileNamesLinkText {
+			return nil, fmt.Errorf("%s: league %q has unknown file_names %q", path, l.Key, l.FileNames)
+		}
+		for _, k := range []string{strings.ToLower(l.Key), strings.ToLower(l.Code)} {
+			if seen[k] {
+				return nil, fmt.Errorf("%s: duplicate league key or code %q", path, k)
+			}
+			seen[k] = true
+		}
+	}
+	reg.Root = filepath.Dir(path)
+	return &reg, nil
+}
+
+// Locate finds leagues.json: $LEAGUES_FILE when set, otherwise the working
+// directory or up to two parents, so commands work from backend/ or from
+// their own cmd/ directory.
+func Locate() (string, error) {
+	if p := os.Getenv("LEAGUES_FILE"); p != "" {
+		return p, nil
+	}
+	for _, dir := range []string{".", "..", filepath.Join("..", "..")} {
+		p := filepath.Join(dir, FileName)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found (set LEAGUES_FILE)", FileName)
+}
+
+// LoadDefault loads the registry found by Locate.
+func LoadDefault() (*Registry, error) {
+	path, err := Locate()
+	if err != nil {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Get looks a league up by API key or competition code, ignoring case.
+func (r *Registry) Get(keyOrCode string) (League, bool) {
+	for _, l := range r.Leagues {
+		if strings.EqualFold(l.Key, keyOrCode) || strings.EqualFold(l.Code, keyOrCode) {
+			return l, true
+		}
+	}
+	return League{}, false
+}
+
+// DataDir is the league's team directory resolved against the registry root.
+func (r *Registry) DataDir(l League) string {
+	if filepath.IsAbs(l.Dir) {
+		return l.Dir
+	}
+	return filepath.Join(r.Root, l.Dir)
+}
+
+// SeasonDir is where one season of the league's team JSONs lives:
+// <dir>/<season>/<team>.json.
+func (r *Registry) SeasonDir(l League, season int) string {
+	return filepath.Join(r.DataDir(l), strconv.Itoa(season))
+}
+
+// Seasons lists the seasons scraped for the league, oldest first.
+func (r *Registry) Seasons(l League) ([]int, error) {
+	entries, err := os.ReadDir(r.DataDir(l))
+	if err != nil {
+		return nil, err
+	}
+	var seasons []int
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if n, err := strconv.Atoi(e.Name()); err == nil && n > 0 {
+			seasons = append(seasons, n)
+		}
+	}
+	sort.Ints(seasons)
+	return seasons, nil
+}
+
+// LatestSeason is the most recent scraped season, falling back to the
+// registry's configured season when nothing has been scraped yet.
+func (r *Registry) LatestSeason(l League) int {
+	seasons, err := r.Seasons(l)
+	if err != nil || len(seasons) == 0 {
+		return l.Season
+	}
+	return seasons[len(seasons)-1]
+}
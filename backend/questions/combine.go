@@ -0,0 +1,220 @@
+package questions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"futbol912.com/fuzzy"
+)
+
+// sourceFile is a question as downloaded from playfootball.games.
+type sourceFile struct {
+	GameData struct {
+		Question string   `json:"question"`
+		Answers  []string `json:"answers"`
+	} `json:"gameData"`
+}
+
+// Duplicate is a source question whose text, after folding, another source
+// already has.
+type Duplicate struct {
+	ID       string
+	SourceID string // the skipped source
+	KeptFrom string // the source that keeps the ID
+}
+
+// NearDuplicate is a pair of different questions whose texts differ by at
+// most a few edits; they are kept but worth a manual look.
+type NearDuplicate struct {
+	A, B     Question
+	Distance int
+}
+
+// MergeReport summarises a Merge.
+type MergeReport struct {
+	Added, Updated, Unchanged int
+	Duplicates                []Duplicate
+	NearDuplicates            []NearDuplicate
+	// Invalid lists sources skipped for an empty question or answer list.
+	Invalid []string
+}
+
+// ReadSourceDir reads every <id>.json question downloaded into dir.
+// Unreadable files are returned as errors in the joined error, the rest
+// are still returned.
+func ReadSourceDir(dir string) ([]Question, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []Question
+	var errs []error
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var sf sourceFile
+		if err := json.Unmarshal(b, &sf); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %s: %w", file.Name(), err))
+			continue
+		}
+		sourceID := strings.TrimSuffix(file.Name(), ".json")
+		out = append(out, New(sourceID, sf.GameData.Question, sf.GameData.Answers))
+	}
+	return out, errors.Join(errs...)
+}
+
+// Merge adds incoming questions to existing ones. A question from a source
+// already merged replaces that source's entry and keeps its ID, even when
+// its text changed upstream; one whose folded text another source already
+// has is reported as a duplicate and skipped. The result is sorted by source
+// ID (numerically when possible), then ID.
+func Merge(existing, incoming []Question) ([]Question, MergeReport) {
+	var rep MergeReport
+	byID := map[string]bool{}
+	bySource := map[string]int{} // source ID -> index in out
+	byText := map[string]int{}   // folded text -> index in out
+	out := make([]Question, 0, len(existing)+len(incoming))
+	for _, q := range existing {
+		if byID[q.ID] {
+			continue
+		}
+		byID[q.ID] = true
+		if q.SourceID != "" {
+			bySource[q.SourceID] = len(out)
+		}
+		byText[fuzzy.Fold(q.Text)] = len(out)
+		out = append(out, q)
+	}
+
+	for _, q := range incoming {
+		q.Answers = cleanAnswers(q.Answers)
+		q.fold()
+		if strings.TrimSpace(q.Text) == "" || len(q.Answers) == 0 {
+			rep.Invalid = append(rep.Invalid, q.SourceID)
+			continue
+		}
+		text := fuzzy.Fold(q.Text)
+		i, known := bySource[q.SourceID]
+		if j, ok := byText[text]; ok && (!known || j != i) {
+			rep.Duplicates = append(rep.Duplicates, Duplicate{ID: out[j].ID, SourceID: q.SourceID, KeptFrom: out[j].SourceID})
+			continue
+		}
+		if !known {
+			if q.SourceID != "" {
+				bySource[q.SourceID] = len(out)
+			}
+			byText[text] = len(out)
+			out = append(out, q)
+			rep.Added++
+			continue
+		}
+		// Stored IDs may predate source-keyed ones; the stored one wins.
+		q.ID = out[i].ID
+		if out[i].Text == q.Text && slices.Equal(out[i].Answers, q.Answers) {
+			rep.Unchanged++
+			continue
+		}
+		delete(byText, fuzzy.Fold(out[i].Text))
+		byText[text] = i
+		out[i] = q
+		rep.Updated++
+	}
+
+	Sort(out)
+	rep.NearDuplicates = nearDuplicates(out)
+	return out, rep
+}
+
+// cleanAnswers trims answers and drops empty ones and exact repeats.
+func cleanAnswers(answers []string) []string {
+	out := make([]string, 0, len(answers))
+	seen := map[string]bool{}
+	for _, a := range answers {
+		a = strings.TrimSpace(a)
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		out = append(out, a)
+	}
+	return out
+}
+
+// Sort orders questions by source ID, numerically when both are numbers,
+// then by ID.
+func Sort(qs []Question) {
+	sort.SliceStable(qs, func(i, j int) bool {
+		a, b := qs[i], qs[j]
+		if a.SourceID != b.SourceID {
+			na, errA := strconv.Atoi(a.SourceID)
+			nb, errB := strconv.Atoi(b.SourceID)
+			if errA == nil && errB == nil {
+				return na < nb
+			}
+			return a.SourceID < b.SourceID
+		}
+		return a.ID < b.ID
+	})
+}
+
+// nearDuplicates compares every pair of folded texts. Questions differ in
+// numbers and names often enough that the threshold stays small: a tenth
+// of the shorter text, at most five edits.
+func nearDuplicates(qs []Question) []NearDuplicate {
+	folded := make([]string, len(qs))
+	for i, q := range qs {
+		folded[i] = fuzzy.Fold(q.Text)
+	}
+	var out []NearDuplicate
+	for i := range qs {
+		for j := i + 1; j < len(qs); j++ {
+			limit := min(len(folded[i]), len(folded[j])) / 10
+			limit = min(limit, 5)
+			if limit == 0 || abs(len(folded[i])-len(folded[j])) > limit {
+				continue
+			}
+			if d := fuzzy.Distance(folded[i], folded[j]); d <= limit {
+				out = append(out, NearDuplicate{A: qs[i], B: qs[j], Distance: d})
+			}
+		}
+	}
+	return out
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// CombineDir merges the source questions downloaded into dir into the
+// combined file at out, keeping whatever out already holds.
+func CombineDir(dir, out string) (MergeReport, error) {
+	existing, err := Load(out)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return MergeReport{}, err
+	}
+	incoming, readErr := ReadSourceDir(dir)
+	if incoming == nil && readErr != nil {
+		return MergeReport{}, readErr
+	}
+	merged, rep := Merge(existing, incoming)
+	if err := Save(out, merged); err != nil {
+		return rep, err
+	}
+	return rep, readErr
+}
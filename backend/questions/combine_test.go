@@ -0,0 +1,68 @@
+package questions
+
+import "testing"
+
+func TestMergeChangedText(t *testing.T) {
+	existing := []Question{
+		New("140", "Premier League top scorers", []string{"Alan Shearer", "Harry Kane"}),
+		New("141", "Champions League winners", []string{"Real Madrid"}),
+	}
+	incoming := []Question{
+		New("140", "Premier League all-time top scorers", []string{"Alan Shearer", "Harry Kane"}),
+		New("141", "Champions League winners", []string{"Real Madrid"}),
+		New("142", "Ballon d'Or winners", []string{"Lionel Messi"}),
+	}
+
+	out, rep := Merge(existing, incoming)
+	if rep.Added != 1 || rep.Updated != 1 || rep.Unchanged != 1 || len(rep.Duplicates) != 0 {
+		t.Errorf("added %d, updated %d, unchanged %d, %d duplicates; want 1, 1, 1, 0",
+			rep.Added, rep.Updated, rep.Unchanged, len(rep.Duplicates))
+	}
+	if len(out) != 3 {
+		t.Fatalf("got %d questions, want 3", len(out))
+	}
+	if out[0].SourceID != "140" || out[0].ID != existing[0].ID || out[0].Text != incoming[0].Text {
+		t.Errorf("source 140 kept %q (%s), want the new text %q under its old ID %s", out[0].Text, out[0].ID, incoming[0].Text, existing[0].ID)
+	}
+}
+
+// TestMergeKeepsStoredID covers files combined when IDs were text hashes:
+// the source keeps its stored ID through a rewording.
+func TestMergeKeepsStoredID(t *testing.T) {
+	stored := New("140", "Premier League top scorers", []string{"Alan Shearer"})
+	stored.ID = QuestionID("", stored.Text)
+	incoming := []Question{New("140", "Premier League all-time top scorers", []string{"Alan Shearer"})}
+
+	out, rep := Merge([]Question{stored}, incoming)
+	if rep.Updated != 1 || len(out) != 1 {
+		t.Fatalf("updated %d, %d questions; want 1, 1", rep.Updated, len(out))
+	}
+	if out[0].ID != stored.ID || out[0].Text != incoming[0].Text {
+		t.Errorf("got %q (%s), want %q under %s", out[0].Text, out[0].ID, incoming[0].Text, stored.ID)
+	}
+}
+
+func TestQuestionID(t *testing.T) {
+	if a, b := QuestionID("140", "Top scorers"), QuestionID("140", "All-time top scorers"); a != b {
+		t.Errorf("rewording changed the ID: %s, %s", a, b)
+	}
+	if a, b := QuestionID("140", "Top scorers"), QuestionID("141", "Top scorers"); a == b {
+		t.Errorf("sources 140 and 141 share ID %s", a)
+	}
+	if a, b := QuestionID("", "Top scorers!"), QuestionID("", "top  scorers"); a != b {
+		t.Errorf("text fallback: %s, %s; want the folded text to decide", a, b)
+	}
+}
+
+func TestMergeDuplicate(t *testing.T) {
+	existing := []Question{New("140", "Premier League top scorers", []string{"Alan Shearer"})}
+	incoming := []Question{New("150", "Premier League Top Scorers!", []string{"Harry Kane"})}
+
+	out, rep := Merge(existing, incoming)
+	if len(out) != 1 || out[0].SourceID != "140" {
+		t.Errorf("got %v, want only source 140", out)
+	}
+	if len(rep.Duplicates) != 1 || rep.Duplicates[0].SourceID != "150" || rep.Duplicates[0].KeptFrom != "140" {
+		t.Errorf("duplicates = %+v, want 150 skipped for 140", rep.Duplicates)
+	}
+}
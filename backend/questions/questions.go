@@ -0,0 +1,198 @@
+// Package questions serves the list-style quiz questions ("name every
+// player who...") without exposing their answers: clients get the text and
+// the number of answers, and guesses are checked server-side.
+package questions
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"futbol912.com/fuzzy"
+)
+
+// Difficulty levels, derived from the answer count: a long list is easy to
+// score on, a short one leaves little room.
+const (
+	Easy   = "easy"   // 35 or more answers
+	Medium = "medium" // 25 to 34
+	Hard   = "hard"   // fewer than 25
+)
+
+// Question is one quiz question.
+type Question struct {
+	// ID is stable across re-combines: it follows SourceID, so a question
+	// reworded upstream keeps it. Only questions without a source are keyed
+	// on their text.
+	ID string
+	// SourceID is the playfootball.games file it came from, e.g. "140".
+	SourceID   string
+	Text       string
+	Answers    []string
+	Tags       []string
+	Difficulty string
+
+	folded []string
+}
+
+// New builds a question and computes its ID, tags and difficulty.
+func New(sourceID, text string, answers []string) Question {
+	q := Question{
+		ID:         QuestionID(sourceID, text),
+		SourceID:   sourceID,
+		Text:       text,
+		Answers:    answers,
+		Tags:       Tags(text),
+		Difficulty: DifficultyFor(len(answers)),
+	}
+	q.fold()
+	return q
+}
+
+func (q *Question) fold() {
+	q.folded = q.folded[:0]
+	for _, a := range q.Answers {
+		q.folded = append(q.folded, fuzzy.Fold(a))
+	}
+}
+
+// QuestionID hashes the source ID, so upstream wording changes don't change
+// the ID. Without a source it falls back to the folded text, which at least
+// ignores punctuation, case and accent changes.
+func QuestionID(sourceID, text string) string {
+	key := "text:" + fuzzy.Fold(text)
+	if sourceID != "" {
+		key = "source:" + sourceID
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
+
+// DifficultyFor maps an answer count to Easy, Medium or Hard.
+func DifficultyFor(answers int) string {
+	switch {
+	case answers >= 35:
+		return Easy
+	case answers >= 25:
+		return Medium
+	}
+	return Hard
+}
+
+// entry is a question as stored in all_questions.json. gameData keeps the
+// playfootball.games shape so older readers still work.
+type entry struct {
+	ID         string   `json:"id"`
+	SourceID   string   `json:"source_id,omitempty"`
+	Tags       []string `json:"tags"`
+	Difficulty string   `json:"difficulty"`
+	GameData   struct {
+		Question string   `json:"question"`
+		Answers  []string `json:"answers"`
+	} `json:"gameData"`
+}
+
+type questionsFile struct {
+	Questions []entry `json:"questions"`
+}
+
+// Load reads all_questions.json. Metadata missing from older files is
+// computed on the fly.
+func Load(path string) ([]Question, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f questionsFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	out := make([]Question, 0, len(f.Questions))
+	for _, e := range f.Questions {
+		q := New(e.SourceID, e.GameData.Question, e.GameData.Answers)
+		if e.ID != "" {
+			q.ID = e.ID
+		}
+		if e.Tags != nil {
+			q.Tags = e.Tags
+		}
+		if e.Difficulty != "" {
+			q.Difficulty = e.Difficulty
+		}
+		out = append(out, q)
+	}
+	return out, nil
+}
+
+// Save writes questions in the all_questions.json format. The file is
+// written to a temporary file in the same directory and renamed over path,
+// so readers never see it half written.
+func Save(path string, qs []Question) error {
+	f := questionsFile{Questions: make([]entry, 0, len(qs))}
+	for _, q := range qs {
+		e := entry{ID: q.ID, SourceID: q.SourceID, Tags: q.Tags, Difficulty: q.Difficulty}
+		if e.Tags == nil {
+			e.Tags = []string{}
+		}
+		e.GameData.Question = q.Text
+		e.GameData.Answers = q.Answers
+		f.Questions = append(f.Questions, e)
+	}
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Guess is the outcome of checking a guess against a question.
+type Guess struct {
+	Slot  int          // index into Answers, -1 when nothing matched
+	Match fuzzy.Result // how the guess matched Answers[Slot]
+}
+
+// Check finds the answer slot a guess refers to: the closest match, and
+// among equally close ones a slot for which skip returns false (not yet
+// revealed), so "young" reveals the second Young once the first is found.
+// A revealed slot still wins over a worse match, so the caller can report
+// it as already found instead of giving away another answer.
+func (q Question) Check(guess string, skip func(slot int) bool) Guess {
+	g := fuzzy.Fold(guess)
+	best := Guess{Slot: -1, Match: fuzzy.Result{Kind: fuzzy.None}}
+	bestSkipped := true
+	for i, a := range q.folded {
+		r := fuzzy.MatchAnswerFolded(g, a)
+		if r.Kind == fuzzy.None {
+			continue
+		}
+		skipped := skip != nil && skip(i)
+		if best.Slot == -1 || r.Better(best.Match) || (r == best.Match && bestSkipped && !skipped) {
+			best = Guess{Slot: i, Match: r}
+			bestSkipped = skipped
+		}
+	}
+	return best
+}
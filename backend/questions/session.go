@@ -0,0 +1,186 @@
+package questions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSessionNotFound is returned for unknown or expired sessions.
+	ErrSessionNotFound = errors.New("quiz session not found")
+	// ErrNotInSession is returned for questions the session wasn't dealt.
+	ErrNotInSession = errors.New("question not in session")
+	// ErrFinished is returned for guesses on a question the player gave up.
+	ErrFinished = errors.New("question already finished")
+)
+
+type progress struct {
+	revealed []bool
+	found    int
+	finished bool
+	gaveUp   bool // finished by GiveUp, which showed every answer
+}
+
+type session struct {
+	set       string
+	questions map[string]*progress
+	started   time.Time
+	expires   time.Time
+}
+
+// Sessions tracks which answers each player has revealed. Sessions live in
+// memory and expire after ttl without activity.
+type Sessions struct {
+	ttl time.Duration
+
+	mu sync.Mutex
+	m  map[string]*session
+}
+
+// NewSessions returns an empty session store.
+func NewSessions(ttl time.Duration) *Sessions {
+	return &Sessions{ttl: ttl, m: map[string]*session{}}
+}
+
+// Start opens a session for the given questions and returns its ID.
+func (s *Sessions) Start(qs []Question) (string, error) {
+	return s.StartSet("", qs)
+}
+
+// StartSet is Start for a fixed question set, such as a day's challenge;
+// the set's name comes back in the session's Summary.
+func (s *Sessions) StartSet(set string, qs []Question) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b)
+
+	sess := &session{set: set, questions: map[string]*progress{}}
+	for _, q := range qs {
+		sess.questions[q.ID] = &progress{revealed: make([]bool, len(q.Answers))}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, v := range s.m {
+		if now.After(v.expires) {
+			delete(s.m, k)
+		}
+	}
+	sess.started = now
+	sess.expires = now.Add(s.ttl)
+	s.m[id] = sess
+	return id, nil
+}
+
+// Result is what a guess revealed.
+type Result struct {
+	Correct bool   `json:"correct"`
+	Slot    int    `json:"slot"`             // answer index, -1 when wrong
+	Answer  string `json:"answer,omitempty"` // the revealed answer's spelling
+	Match   string `json:"match,omitempty"`  // exact or fuzzy
+	// Already is set when the guess names an answer revealed earlier.
+	Already  bool `json:"already"`
+	Found    int  `json:"found"`
+	Total    int  `json:"total"`
+	Complete bool `json:"complete"`
+}
+
+// Guess checks a guess for question q in the session and reveals the
+// matching slot.
+func (s *Sessions) Guess(sessionID string, q Question, guess string) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.progress(sessionID, q.ID)
+	if err != nil {
+		return Result{}, err
+	}
+	if p.finished {
+		return Result{}, ErrFinished
+	}
+
+	g := q.Check(guess, func(slot int) bool { return p.revealed[slot] })
+	res := Result{Slot: g.Slot, Total: len(q.Answers)}
+	if g.Slot >= 0 {
+		res.Correct = true
+		res.Answer = q.Answers[g.Slot]
+		res.Match = g.Match.Kind.String()
+		if p.revealed[g.Slot] {
+			res.Already = true
+		} else {
+			p.revealed[g.Slot] = true
+			p.found++
+		}
+	}
+	res.Found = p.found
+	res.Complete = p.found == len(q.Answers)
+	return res, nil
+}
+
+// GiveUp ends question q for the session and returns every answer with
+// whether it was found.
+func (s *Sessions) GiveUp(sessionID string, q Question) ([]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.progress(sessionID, q.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.finished = true
+	p.gaveUp = true
+	return append([]bool(nil), p.revealed...), nil
+}
+
+// Summary is a finished session's tally.
+type Summary struct {
+	Questions int       `json:"questions"`
+	Found     int       `json:"found"` // answers revealed by guessing
+	Total     int       `json:"total"` // answers across all questions
+	Started   time.Time `json:"started"`
+	Set       string    `json:"set,omitempty"` // see StartSet
+	// GivenUp is set when the session gave up on one of its questions.
+	GivenUp bool `json:"given_up"`
+}
+
+// Finish ends every question of the session, so no more guesses count,
+// and returns its tally. Finishing again returns the same tally.
+func (s *Sessions) Finish(sessionID string) (Summary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.m[sessionID]
+	if !ok || time.Now().After(sess.expires) {
+		delete(s.m, sessionID)
+		return Summary{}, ErrSessionNotFound
+	}
+	sess.expires = time.Now().Add(s.ttl)
+	sum := Summary{Questions: len(sess.questions), Started: sess.started, Set: sess.set}
+	for _, p := range sess.questions {
+		p.finished = true
+		sum.GivenUp = sum.GivenUp || p.gaveUp
+		sum.Found += p.found
+		sum.Total += len(p.revealed)
+	}
+	return sum, nil
+}
+
+func (s *Sessions) progress(sessionID, questionID string) (*progress, error) {
+	sess, ok := s.m[sessionID]
+	if !ok || time.Now().After(sess.expires) {
+		delete(s.m, sessionID)
+		return nil, ErrSessionNotFound
+	}
+	sess.expires = time.Now().Add(s.ttl)
+	p, ok := sess.questions[questionID]
+	if !ok {
+		return nil, ErrNotInSession
+	}
+	return p, nil
+}
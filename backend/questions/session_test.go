@@ -0,0 +1,93 @@
+package questions
+
+import (
+	"testing"
+	"time"
+)
+
+func guess(t *testing.T, s *Sessions, id string, q Question, text string) Result {
+	t.Helper()
+	res, err := s.Guess(id, q, text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// TestGuessRepeatedAnswer checks that guessing a found answer again reports
+// it as already found instead of revealing a fuzzy match for another one.
+func TestGuessRepeatedAnswer(t *testing.T) {
+	q := New("1", "Scored in the final", []string{"Harry Kane", "Sadio Mané", "Mohamed Salah"})
+	s := NewSessions(time.Hour)
+	id, err := s.Start([]Question{q})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res := guess(t, s, id, q, "kane"); res.Slot != 0 || res.Already {
+		t.Fatalf("first kane: slot %d, already %v; want slot 0", res.Slot, res.Already)
+	}
+	res := guess(t, s, id, q, "kane")
+	if res.Slot != 0 || !res.Already || res.Found != 1 {
+		t.Errorf("second kane: slot %d (%s), already %v, found %d; want slot 0 already found",
+			res.Slot, res.Answer, res.Already, res.Found)
+	}
+}
+
+// TestGuessSharedSurname checks that equally good matches go to the first
+// open slot: "young" reveals both Youngs in turn.
+func TestGuessSharedSurname(t *testing.T) {
+	q := New("2", "Played for England", []string{"Ashley Young", "Luke Young", "Wayne Rooney"})
+	s := NewSessions(time.Hour)
+	id, err := s.Start([]Question{q})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for want := 0; want < 2; want++ {
+		if res := guess(t, s, id, q, "young"); res.Slot != want || res.Already {
+			t.Errorf("guess %d: slot %d, already %v; want slot %d", want+1, res.Slot, res.Already, want)
+		}
+	}
+	if res := guess(t, s, id, q, "young"); !res.Already {
+		t.Errorf("third young: slot %d, already %v; want an already found Young", res.Slot, res.Already)
+	}
+}
+
+// TestFinishGivenUp checks that a give-up only leaves the session that
+// gave up unranked: anyone could otherwise give up on a daily question and
+// spoil every player's result.
+func TestFinishGivenUp(t *testing.T) {
+	q := New("1", "Scored in the final", []string{"Harry Kane", "Sadio Mané"})
+	s := NewSessions(time.Hour)
+	a, err := s.StartSet("daily/2024-05-01", []Question{q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.StartSet("daily/2024-05-01", []Question{q})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guess(t, s, a, q, "kane")
+	if _, err := s.GiveUp(a, q); err != nil {
+		t.Fatal(err)
+	}
+	guess(t, s, b, q, "kane")
+	guess(t, s, b, q, "mane")
+
+	sum, err := s.Finish(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sum.GivenUp || sum.Found != 1 {
+		t.Errorf("session A summary = %+v, want given up with 1 found", sum)
+	}
+	sum, err = s.Finish(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.GivenUp || sum.Set != "daily/2024-05-01" || sum.Found != 2 {
+		t.Errorf("session B summary = %+v, want the daily set not given up with 2 found", sum)
+	}
+}
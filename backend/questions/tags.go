@@ -0,0 +1,147 @@
+package questions
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"futbol912.com/fuzzy"
+)
+
+// Tag prefixes. Tags look like "league:premier-league", "club:arsenal",
+// "nation:spain" or "era:2010s".
+const (
+	TagLeague = "league"
+	TagClub   = "club"
+	TagNation = "nation"
+	TagEra    = "era"
+)
+
+type keyword struct {
+	tag     string
+	phrases []string // folded, matched on word boundaries
+}
+
+// Longer phrases come first within a group so "club world cup" is claimed
+// before "world cup".
+var leagueKeywords = []keyword{
+	{"club-world-cup", []string{"club world cup"}},
+	{"world-cup", []string{"world cup"}},
+	{"champions-league", []string{"champions league", "european cup"}},
+	{"europa-league", []string{"europa league", "uefa cup"}},
+	{"super-cup", []string{"super cup"}},
+	{"euro", []string{"euro 2000", "euro 2004", "euro 2008", "euro 2012", "euro 2016", "euro 2020", "euro 2024", "euros"}},
+	{"premier-league", []string{"premier league", "barclays"}},
+	{"championship", []string{"english second tier", "championship"}},
+	{"fa-cup", []string{"fa cup"}},
+	{"laliga", []string{"laliga", "la liga"}},
+	{"serie-a", []string{"serie a"}},
+	{"bundesliga", []string{"bundesliga"}},
+	{"dfb-pokal", []string{"dfb pokal"}},
+	{"ligue-1", []string{"ligue 1"}},
+	{"eredivisie", []string{"eredivisie"}},
+	{"big-5", []string{"big 5"}},
+	{"ballon-dor", []string{"ballon d or"}},
+}
+
+var clubKeywords = []keyword{
+	{"manchester-united", []string{"manchester united", "man utd"}},
+	{"manchester-city", []string{"manchester city", "man city"}},
+	{"tottenham", []string{"tottenham"}},
+	{"arsenal", []string{"arsenal"}},
+	{"chelsea", []string{"chelsea"}},
+	{"liverpool", []string{"liverpool"}},
+	{"leicester-city", []string{"leicester city", "leicester"}},
+	{"real-madrid", []string{"real madrid"}},
+	{"barcelona", []string{"barcelona"}},
+	{"villarreal", []string{"villarreal"}},
+	{"bayern-munich", []string{"bayern munich"}},
+	{"borussia-dortmund", []string{"borussia dortmund", "dortmund"}},
+	{"juventus", []string{"juventus"}},
+	{"inter-milan", []string{"inter milan"}},
+	{"ac-milan", []string{"ac milan"}},
+	{"roma", []string{"roma"}},
+	{"napoli", []string{"napoli"}},
+	{"paris-saint-germain", []string{"paris saint germain", "psg"}},
+	{"ajax", []string{"ajax"}},
+	{"rangers", []string{"rangers"}},
+}
+
+// nationKeywords only match in national-team phrasing ("for Spain",
+// "Spain's squad", "caps for Spain"), not nationality adjectives.
+var nationKeywords = []keyword{
+	{"argentina", []string{"argentina"}},
+	{"brazil", []string{"brazil"}},
+	{"england", []string{"england"}},
+	{"france", []string{"france"}},
+	{"germany", []string{"germany"}},
+	{"italy", []string{"italy"}},
+	{"portugal", []string{"portugal"}},
+	{"spain", []string{"spain"}},
+	{"croatia", []string{"croatia"}},
+}
+
+var (
+	reYear   = regexp.MustCompile(`\b(19[5-9]\d|20[0-4]\d)\b`)
+	reDecade = regexp.MustCompile(`\b(19[5-9]0|20[0-4]0)s\b`)
+)
+
+// Tags derives league, club, nation and era tags from a question's text.
+// They are sorted and deduplicated.
+func Tags(text string) []string {
+	folded := " " + fuzzy.Fold(text) + " "
+	set := map[string]bool{}
+
+	claim := func(prefix string, kws []keyword) {
+		for _, kw := range kws {
+			for _, p := range kw.phrases {
+				if i := strings.Index(folded, " "+p+" "); i >= 0 {
+					set[prefix+":"+kw.tag] = true
+					// blank it so shorter phrases can't match it again
+					folded = folded[:i+1] + strings.Repeat("_", len(p)) + folded[i+1+len(p):]
+					break
+				}
+			}
+		}
+	}
+	claim(TagLeague, leagueKeywords)
+	claim(TagClub, clubKeywords)
+	for _, kw := range nationKeywords {
+		for _, p := range kw.phrases {
+			if strings.Contains(folded, " for "+p+" ") || strings.Contains(folded, " "+p+" s ") {
+				set[TagNation+":"+kw.tag] = true
+			}
+		}
+	}
+
+	for _, m := range reDecade.FindAllString(text, -1) {
+		set[TagEra+":"+m] = true
+	}
+	for _, m := range reYear.FindAllString(text, -1) {
+		y, _ := strconv.Atoi(m)
+		set[TagEra+":"+strconv.Itoa(y/10*10)+"s"] = true
+	}
+
+	out := make([]string, 0, len(set))
+	for t := range set {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// HasTag reports whether tags contain want, either in full ("club:arsenal")
+// or by value alone ("arsenal").
+func HasTag(tags []string, want string) bool {
+	want = strings.ToLower(strings.TrimSpace(want))
+	for _, t := range tags {
+		if t == want {
+			return true
+		}
+		if _, v, ok := strings.Cut(t, ":"); ok && v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package sessions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Manager runs sessions on top of a Store and signs their results.
+type Manager struct {
+	store  Store
+	secret []byte
+
+	// mu serializes read-modify-write cycles so two answers to the same
+	// session can't both be scored against the same round.
+	mu sync.Mutex
+}
+
+// NewManager returns a manager signing results with secret.
+func NewManager(store Store, secret []byte) *Manager {
+	return &Manager{store: store, secret: secret}
+}
+
+// Start stores a newly dealt session.
+func (m *Manager) Start(s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.store.Put(s)
+}
+
+// Get returns a session. Timed sessions that ran out are finished on read.
+func (m *Manager) Get(id string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Finished() && s.Expired(time.Now()) {
+		s.Finish(time.Now())
+		if err := m.store.Put(s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Answer submits an answer to the session's current round.
+func (m *Manager) Answer(id string, a Answer) (*Session, Outcome, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.store.Get(id)
+	if err != nil {
+		return nil, Outcome{}, err
+	}
+	out, err := s.Submit(a, time.Now())
+	// A timed-out submit still finishes the session, so store it either
+	// way.
+	if perr := m.store.Put(s); perr != nil && err == nil {
+		err = perr
+	}
+	if err != nil {
+		return s, Outcome{}, err
+	}
+	return s, out, nil
+}
+
+// Finish ends the session and returns its signed result. Finishing twice
+// returns the same result.
+func (m *Manager) Finish(id string) (Result, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.store.Get(id)
+	if err != nil {
+		return Result{}, "", err
+	}
+	s.Finish(time.Now())
+	if err := m.store.Put(s); err != nil {
+		return Result{}, "", err
+	}
+	res := s.Result()
+	sig, err := Sign(m.secret, res)
+	if err != nil {
+		return Result{}, "", err
+	}
+	return res, sig, nil
+}
+
+// Sign signs a result built elsewhere, e.g. a finished list quiz.
+func (m *Manager) Sign(res Result) (string, error) {
+	return Sign(m.secret, res)
+}
+
+// Verify reports whether sig is this manager's signature of res.
+func (m *Manager) Verify(res Result, sig string) bool {
+	return Verify(m.secret, res, sig)
+}
+
+// Sign returns the hex HMAC-SHA256 of the result's JSON encoding.
+func Sign(secret []byte, res Result) (string, error) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Verify checks a signature made by Sign.
+func Verify(secret []byte, res Result, sig string) bool {
+	want, err := Sign(secret, res)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(want), []byte(sig))
+}
@@ -0,0 +1,59 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManagerFinishSigns(t *testing.T) {
+	secret := []byte("secret")
+	m := NewManager(NewMemoryStore(time.Hour), secret)
+	s, err := New(ModeAge, []Round{{Key: Key{Age: 30}}, {Key: Key{Age: 25}}}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(s); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Answer(s.ID, Answer{Round: 0, Age: 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, sig, err := m.Finish(s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Session != s.ID || res.Answered != 1 || res.Rounds != 2 || res.Score < 10 {
+		t.Errorf("result = %+v, want one answered round of two scoring at least 10", res)
+	}
+	if !m.Verify(res, sig) || !Verify(secret, res, sig) {
+		t.Error("Verify rejected the manager's own signature")
+	}
+
+	again, sig2, err := m.Finish(s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != res || sig2 != sig {
+		t.Errorf("second Finish = %+v %s, want the first result %+v %s", again, sig2, res, sig)
+	}
+
+	tampered := res
+	tampered.Score += 100
+	if m.Verify(tampered, sig) {
+		t.Error("Verify accepted a tampered score")
+	}
+	flipped := []byte(sig)
+	flipped[0] ^= 1
+	if m.Verify(res, string(flipped)) {
+		t.Error("Verify accepted a tampered signature")
+	}
+	if Verify([]byte("other"), res, sig) {
+		t.Error("Verify accepted a signature made with another secret")
+	}
+
+	if _, _, err := m.Answer(s.ID, Answer{Round: 1, Age: 25}); !errors.Is(err, ErrFinished) {
+		t.Errorf("answer after Finish: err = %v, want ErrFinished", err)
+	}
+}
@@ -0,0 +1,239 @@
+package sessions
+
+import (
+	"time"
+
+	"futbol912.com/fuzzy"
+)
+
+const (
+	// BasePoints is what a correct answer is worth before bonuses.
+	BasePoints = 10
+	// WrongCellPenalty is taken off for placing a bingo player in a cell
+	// they don't fit. The score never drops below zero.
+	WrongCellPenalty = 5
+	// MaxTimeBonus is earned by a correct answer given instantly; it
+	// shrinks linearly to zero over TimeBonusWindow.
+	MaxTimeBonus    = 5
+	TimeBonusWindow = 10 * time.Second
+	// StreakBonusStep is added per consecutive correct answer after the
+	// first, up to MaxStreakBonus.
+	StreakBonusStep = 2
+	MaxStreakBonus  = 10
+	// BingoTimeLimit is the length of a bingo game.
+	BingoTimeLimit = 60 * time.Second
+)
+
+// agePoints mirrors the age quiz's closeness scale: 10 for the exact age
+// down to 2 for five years off.
+func agePoints(guess, age int) int {
+	diff := guess - age
+	if diff < 0 {
+		diff = -diff
+	}
+	switch {
+	case diff == 0:
+		return 10
+	case diff == 1:
+		return 8
+	case diff == 2:
+		return 6
+	case diff <= 3:
+		return 4
+	case diff <= 5:
+		return 2
+	}
+	return 0
+}
+
+// timeBonus is the bonus for a correct answer given elapsed after the round
+// was dealt.
+func timeBonus(elapsed time.Duration) int {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed >= TimeBonusWindow {
+		return 0
+	}
+	left := TimeBonusWindow - elapsed
+	return int((time.Duration(MaxTimeBonus)*left + TimeBonusWindow - 1) / TimeBonusWindow)
+}
+
+func streakBonus(streak int) int {
+	return min((streak-1)*StreakBonusStep, MaxStreakBonus)
+}
+
+// Expired reports whether a timed session ran out of time at now.
+func (s *Session) Expired(now time.Time) bool {
+	return s.TimeLimit > 0 && now.Sub(s.StartedAt) >= s.TimeLimit
+}
+
+// Submit scores an answer to the current round and deals the next one. The
+// session finishes by itself after the last round, or when a timed session
+// runs out of time (the late answer is rejected with ErrFinished).
+func (s *Session) Submit(a Answer, now time.Time) (Outcome, error) {
+	if s.Finished() {
+		return Outcome{}, ErrFinished
+	}
+	s.LastActivity = now
+	if s.Expired(now) {
+		s.finish(s.StartedAt.Add(s.TimeLimit))
+		return Outcome{}, ErrFinished
+	}
+	if a.Round != s.Current {
+		return Outcome{}, ErrWrongRound
+	}
+
+	key := s.Rounds[s.Current].Key
+	points, correct, err := s.check(key, a)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	elapsed := now.Sub(s.RoundStartedAt)
+	out := Outcome{
+		Round:     s.Current,
+		Correct:   correct,
+		Skipped:   a.Skip,
+		Points:    points,
+		ElapsedMS: elapsed.Milliseconds(),
+		Key:       key,
+	}
+	if correct {
+		s.Correct++
+		s.Streak++
+		s.BestStreak = max(s.BestStreak, s.Streak)
+		out.TimeBonus = timeBonus(elapsed)
+		out.StreakBonus = streakBonus(s.Streak)
+	} else if !a.Skip {
+		// Passing on a player who fits no open cell is no mistake, so a
+		// skip keeps the streak.
+		s.Streak = 0
+	}
+	out.Streak = s.Streak
+	s.Score = max(0, s.Score+out.Points+out.TimeBonus+out.StreakBonus)
+	s.Outcomes = append(s.Outcomes, out)
+
+	s.Current++
+	s.RoundStartedAt = now
+	if s.Current >= len(s.Rounds) || s.boardFull() {
+		s.finish(now)
+	}
+	return out, nil
+}
+
+// Finish ends the session early; answers after it are rejected.
+func (s *Session) Finish(now time.Time) {
+	if s.Finished() {
+		return
+	}
+	s.LastActivity = now
+	if s.Expired(now) {
+		now = s.StartedAt.Add(s.TimeLimit)
+	}
+	s.finish(now)
+}
+
+func (s *Session) finish(at time.Time) {
+	s.FinishedAt = at
+}
+
+// check scores an answer against key. Age answers earn partial points and
+// count as correct when they earn any.
+func (s *Session) check(key Key, a Answer) (points int, correct bool, err error) {
+	if a.Skip && s.Mode != ModeBingo {
+		return 0, false, ErrInvalidAnswer
+	}
+	switch s.Mode {
+	case ModeAge:
+		if a.Age <= 0 {
+			return 0, false, ErrInvalidAnswer
+		}
+		points = agePoints(a.Age, key.Age)
+		return points, points > 0, nil
+
+	case ModeNationality:
+		if a.Choice == "" {
+			return 0, false, ErrInvalidAnswer
+		}
+		choice := fuzzy.Fold(a.Choice)
+		for _, n := range key.Accept {
+			if fuzzy.Fold(n) == choice {
+				return BasePoints, true, nil
+			}
+		}
+		return 0, false, nil
+
+	case ModeTeam:
+		if len(a.Players) == 0 {
+			return 0, false, ErrInvalidAnswer
+		}
+		if sameSet(a.Players, key.Players) {
+			return BasePoints, true, nil
+		}
+		return 0, false, nil
+
+	case ModeBingo:
+		if a.Skip {
+			if a.Cell != nil {
+				return 0, false, ErrInvalidAnswer
+			}
+			return 0, false, nil
+		}
+		if a.Cell == nil || *a.Cell < 0 || *a.Cell >= len(s.Cells) || s.Used[*a.Cell] {
+			return 0, false, ErrInvalidAnswer
+		}
+		// The cell is played either way, as on the client: a wrong
+		// placement burns it.
+		s.Used[*a.Cell] = true
+		if fitsCell(key.Categories, s.Cells[*a.Cell]) {
+			return BasePoints, true, nil
+		}
+		return -WrongCellPenalty, false, nil
+	}
+	return 0, false, ErrUnknownMode
+}
+
+func (s *Session) boardFull() bool {
+	if s.Mode != ModeBingo {
+		return false
+	}
+	for _, used := range s.Used {
+		if !used {
+			return false
+		}
+	}
+	return true
+}
+
+func fitsCell(categories, cell []int) bool {
+	if len(cell) == 0 {
+		return false
+	}
+	has := map[int]bool{}
+	for _, c := range categories {
+		has[c] = true
+	}
+	for _, c := range cell {
+		if !has[c] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameSet(a, b []string) bool {
+	set := map[string]bool{}
+	for _, v := range a {
+		set[v] = true
+	}
+	if len(set) != len(b) {
+		return false
+	}
+	for _, v := range b {
+		if !set[v] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// bingoSession deals three players onto a two-cell board: the first fits
+// cell 0, the second fits no cell and the third fits cell 1.
+func bingoSession(t *testing.T, now time.Time) *Session {
+	t.Helper()
+	s, err := New(ModeBingo, []Round{
+		{Key: Key{Categories: []int{1}}},
+		{Key: Key{Categories: []int{9}}},
+		{Key: Key{Categories: []int{2}}},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Cells = [][]int{{1}, {2}}
+	s.Used = make([]bool, 2)
+	s.TimeLimit = BingoTimeLimit
+	return s
+}
+
+func cell(i int) *int { return &i }
+
+func TestBingoSkip(t *testing.T) {
+	now := time.Now()
+	s := bingoSession(t, now)
+
+	if _, err := s.Submit(Answer{Round: 0, Cell: cell(0)}, now.Add(20*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	score, streak := s.Score, s.Streak
+
+	out, err := s.Submit(Answer{Round: 1, Skip: true}, now.Add(21*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Skipped || out.Correct || out.Points != 0 || out.TimeBonus != 0 || out.StreakBonus != 0 {
+		t.Errorf("skip outcome = %+v, want a skipped round worth nothing", out)
+	}
+	if s.Score != score || s.Streak != streak {
+		t.Errorf("after the skip: score %d, streak %d; want %d, %d", s.Score, s.Streak, score, streak)
+	}
+	if s.Used[1] || s.Current != 2 || s.Finished() {
+		t.Errorf("after the skip: used %v, current %d, finished %v; want cell 1 open on round 2", s.Used, s.Current, s.Finished())
+	}
+
+	out, err = s.Submit(Answer{Round: 2, Cell: cell(1)}, now.Add(22*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Correct || !s.Finished() {
+		t.Errorf("last player: correct %v, finished %v; want the skipped cell still playable", out.Correct, s.Finished())
+	}
+}
+
+func TestBingoSkipInvalid(t *testing.T) {
+	now := time.Now()
+	s := bingoSession(t, now)
+	if _, err := s.Submit(Answer{Round: 0, Skip: true, Cell: cell(0)}, now); !errors.Is(err, ErrInvalidAnswer) {
+		t.Errorf("skip with a cell: err = %v, want ErrInvalidAnswer", err)
+	}
+
+	age, err := New(ModeAge, []Round{{Key: Key{Age: 30}}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := age.Submit(Answer{Round: 0, Skip: true}, now); !errors.Is(err, ErrInvalidAnswer) {
+		t.Errorf("skip in the age quiz: err = %v, want ErrInvalidAnswer", err)
+	}
+}
+
+func TestAgePoints(t *testing.T) {
+	tests := []struct{ guess, want int }{
+		{30, 10},
+		{31, 8}, {29, 8},
+		{32, 6}, {28, 6},
+		{33, 4}, {27, 4},
+		{34, 2}, {35, 2}, {25, 2},
+		{36, 0}, {24, 0}, {1, 0},
+	}
+	for _, tt := range tests {
+		if got := agePoints(tt.guess, 30); got != tt.want {
+			t.Errorf("agePoints(%d, 30) = %d, want %d", tt.guess, got, tt.want)
+		}
+	}
+}
+
+func TestTimeBonus(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{-time.Second, MaxTimeBonus}, // a client clock ahead of ours
+		{0, MaxTimeBonus},
+		{TimeBonusWindow / 2, 3},
+		{TimeBonusWindow - time.Millisecond, 1},
+		{TimeBonusWindow, 0},
+		{time.Minute, 0},
+	}
+	for _, tt := range tests {
+		if got := timeBonus(tt.elapsed); got != tt.want {
+			t.Errorf("timeBonus(%v) = %d, want %d", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestStreakBonus(t *testing.T) {
+	tests := []struct{ streak, want int }{
+		{1, 0},
+		{2, StreakBonusStep},
+		{6, MaxStreakBonus},
+		{7, MaxStreakBonus},
+		{100, MaxStreakBonus},
+	}
+	for _, tt := range tests {
+		if got := streakBonus(tt.streak); got != tt.want {
+			t.Errorf("streakBonus(%d) = %d, want %d", tt.streak, got, tt.want)
+		}
+	}
+}
+
+func TestCheckNationality(t *testing.T) {
+	s := &Session{Mode: ModeNationality}
+	key := Key{Accept: []string{"Francia", "Camerún"}}
+	tests := []struct {
+		choice  string
+		correct bool
+		err     error
+	}{
+		{"Francia", true, nil},
+		{"camerun", true, nil}, // folded like the client's typing
+		{"  CAMERÚN ", true, nil},
+		{"España", false, nil},
+		{"", false, ErrInvalidAnswer},
+	}
+	for _, tt := range tests {
+		points, correct, err := s.check(key, Answer{Choice: tt.choice})
+		if correct != tt.correct || !errors.Is(err, tt.err) {
+			t.Errorf("check(%q) = %v, %v; want %v, %v", tt.choice, correct, err, tt.correct, tt.err)
+		}
+		if want := map[bool]int{true: BasePoints}[tt.correct]; points != want {
+			t.Errorf("check(%q) points = %d, want %d", tt.choice, points, want)
+		}
+	}
+}
+
+func TestCheckTeam(t *testing.T) {
+	s := &Session{Mode: ModeTeam}
+	key := Key{Players: []string{"10", "20"}}
+	tests := []struct {
+		players []string
+		correct bool
+		err     error
+	}{
+		{[]string{"10", "20"}, true, nil},
+		{[]string{"20", "10"}, true, nil},
+		{[]string{"10"}, false, nil},
+		{[]string{"10", "30"}, false, nil},
+		{[]string{"10", "20", "30"}, false, nil},
+		{nil, false, ErrInvalidAnswer},
+	}
+	for _, tt := range tests {
+		points, correct, err := s.check(key, Answer{Players: tt.players})
+		if correct != tt.correct || !errors.Is(err, tt.err) {
+			t.Errorf("check(%v) = %v, %v; want %v, %v", tt.players, correct, err, tt.correct, tt.err)
+		}
+		if want := map[bool]int{true: BasePoints}[tt.correct]; points != want {
+			t.Errorf("check(%v) points = %d, want %d", tt.players, points, want)
+		}
+	}
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Now()
+	untimed := &Session{StartedAt: now}
+	if untimed.Expired(now.Add(24 * time.Hour)) {
+		t.Error("a session without a time limit expired")
+	}
+
+	s := bingoSession(t, now)
+	if s.Expired(now.Add(BingoTimeLimit - time.Millisecond)) {
+		t.Error("bingo expired before its time limit")
+	}
+	if !s.Expired(now.Add(BingoTimeLimit)) {
+		t.Error("bingo not expired at its time limit")
+	}
+
+	if _, err := s.Submit(Answer{Round: 0, Cell: cell(0)}, now.Add(BingoTimeLimit+time.Second)); !errors.Is(err, ErrFinished) {
+		t.Errorf("late answer: err = %v, want ErrFinished", err)
+	}
+	if want := now.Add(BingoTimeLimit); !s.FinishedAt.Equal(want) {
+		t.Errorf("FinishedAt = %v, want the end of the time limit %v", s.FinishedAt, want)
+	}
+	if len(s.Outcomes) != 0 || s.Score != 0 {
+		t.Errorf("late answer scored: %d outcomes, score %d", len(s.Outcomes), s.Score)
+	}
+}
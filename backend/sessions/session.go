@@ -0,0 +1,194 @@
+// Package sessions runs scored game sessions server-side. A session is dealt
+// a list of rounds up front; the client sees one round's prompt at a time
+// and the answer key never leaves the server. Score, streak and time bonus
+// are computed here, and the final result is signed so it can be handed to
+// other services (leaderboards) without trusting the client.
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Mode is the game a session plays.
+type Mode string
+
+const (
+	ModeAge         Mode = "age"
+	ModeTeam        Mode = "team"
+	ModeNationality Mode = "nationality"
+	ModeBingo       Mode = "bingo"
+
+	// ModeQuestions is the list quiz. It isn't played through a Session
+	// (questions.Sessions tracks it) but its results are signed here too.
+	ModeQuestions Mode = "questions"
+)
+
+// Modes lists every mode a Session can play, in the order the API
+// documents them.
+var Modes = []Mode{ModeAge, ModeTeam, ModeNationality, ModeBingo}
+
+// ParseMode validates a mode name.
+func ParseMode(s string) (Mode, error) {
+	for _, m := range Modes {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+	return "", ErrUnknownMode
+}
+
+var (
+	// ErrNotFound is returned for unknown or expired sessions.
+	ErrNotFound = errors.New("game session not found")
+	// ErrUnknownMode is returned for modes not in Modes.
+	ErrUnknownMode = errors.New("unknown game mode")
+	// ErrFinished is returned for answers to a finished session.
+	ErrFinished = errors.New("game session already finished")
+	// ErrWrongRound is returned when an answer names a round other than
+	// the one being played, e.g. a retried request.
+	ErrWrongRound = errors.New("answer is not for the current round")
+	// ErrInvalidAnswer is returned for answers missing the field the mode
+	// needs, or naming a bingo cell that doesn't exist or is taken.
+	ErrInvalidAnswer = errors.New("invalid answer for this mode")
+)
+
+// Key is a round's answer key. Only the fields of the session's mode are
+// set.
+type Key struct {
+	Age        int      `json:"age,omitempty"`        // age
+	Accept     []string `json:"accept,omitempty"`     // nationality: any of these
+	Players    []string `json:"players,omitempty"`    // team: the teammates' IDs
+	Categories []int    `json:"categories,omitempty"` // bingo: the player's categories
+}
+
+// Round is one dealt question. Prompt is what the client is shown, already
+// encoded by the caller, so the store doesn't need to know each mode's
+// shape.
+type Round struct {
+	Prompt json.RawMessage `json:"prompt"`
+	Key    Key             `json:"key"`
+}
+
+// Answer is a client's answer to the current round. Like Key, only the
+// mode's field is read.
+type Answer struct {
+	Round   int      `json:"round"`
+	Age     int      `json:"age,omitempty"`
+	Choice  string   `json:"choice,omitempty"`
+	Players []string `json:"players,omitempty"`
+	Cell    *int     `json:"cell,omitempty"`
+	// Skip passes on a bingo player without playing a cell, as the
+	// client's skip button does.
+	Skip bool `json:"skip,omitempty"`
+}
+
+// Outcome is the scoring of one answer.
+type Outcome struct {
+	Round     int  `json:"round"`
+	Correct   bool `json:"correct"`
+	Points    int  `json:"points"`     // base points, negative for a wrong bingo cell
+	TimeBonus int  `json:"time_bonus"` // bonus for answering quickly
+	Streak    int  `json:"streak"`     // streak after this answer
+	// StreakBonus is added on top of Points for consecutive correct
+	// answers.
+	StreakBonus int   `json:"streak_bonus"`
+	ElapsedMS   int64 `json:"elapsed_ms"`
+	// Key is revealed once the round is answered.
+	Key Key `json:"key"`
+	// Skipped is set when a bingo player was passed on without a cell.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// Session is a game in progress. It is a plain value so stores other than
+// MemoryStore can serialize it.
+type Session struct {
+	ID     string  `json:"id"`
+	Mode   Mode    `json:"mode"`
+	Rounds []Round `json:"rounds"`
+	// Current is the index of the round being played; len(Rounds) once
+	// every round is answered.
+	Current    int       `json:"current"`
+	Score      int       `json:"score"`
+	Correct    int       `json:"correct"`
+	Streak     int       `json:"streak"`
+	BestStreak int       `json:"best_streak"`
+	Outcomes   []Outcome `json:"outcomes"`
+
+	// Cells and Used hold the bingo board: the categories each cell needs
+	// and whether it has been played.
+	Cells [][]int `json:"cells,omitempty"`
+	Used  []bool  `json:"used,omitempty"`
+	// TimeLimit ends the whole game when set (bingo); it counts from
+	// StartedAt.
+	TimeLimit time.Duration `json:"time_limit,omitempty"`
+
+	StartedAt      time.Time `json:"started_at"`
+	RoundStartedAt time.Time `json:"round_started_at"`
+	LastActivity   time.Time `json:"last_activity"`
+	FinishedAt     time.Time `json:"finished_at,omitempty"`
+}
+
+// New deals a session with a fresh random ID.
+func New(mode Mode, rounds []Round, now time.Time) (*Session, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return &Session{
+		ID:             hex.EncodeToString(b),
+		Mode:           mode,
+		Rounds:         rounds,
+		Outcomes:       []Outcome{},
+		StartedAt:      now,
+		RoundStartedAt: now,
+		LastActivity:   now,
+	}, nil
+}
+
+// Finished reports whether the session takes no more answers.
+func (s *Session) Finished() bool {
+	return !s.FinishedAt.IsZero()
+}
+
+// Round returns the prompt of the round being played, or nil once every
+// round is answered.
+func (s *Session) Round() json.RawMessage {
+	if s.Current >= len(s.Rounds) {
+		return nil
+	}
+	return s.Rounds[s.Current].Prompt
+}
+
+// Result is the final, signable summary of a session.
+type Result struct {
+	Session    string    `json:"session"`
+	Mode       Mode      `json:"mode"`
+	Score      int       `json:"score"`
+	Correct    int       `json:"correct"`
+	Answered   int       `json:"answered"`
+	Rounds     int       `json:"rounds"`
+	BestStreak int       `json:"best_streak"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Result summarizes the session. It is only final once the session is
+// finished. Times are cut to milliseconds so a result that went through a
+// client's JSON still verifies.
+func (s *Session) Result() Result {
+	return Result{
+		Session:    s.ID,
+		Mode:       s.Mode,
+		Score:      s.Score,
+		Correct:    s.Correct,
+		Answered:   len(s.Outcomes),
+		Rounds:     len(s.Rounds),
+		BestStreak: s.BestStreak,
+		StartedAt:  s.StartedAt.UTC().Truncate(time.Millisecond),
+		FinishedAt: s.FinishedAt.UTC().Truncate(time.Millisecond),
+	}
+}
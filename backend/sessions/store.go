@@ -0,0 +1,71 @@
+package sessions
+
+import (
+	"sync"
+	"time"
+)
+
+// Store keeps sessions between requests. Get returns ErrNotFound for
+// unknown sessions and for sessions idle longer than the store's TTL.
+// Implementations must be safe for concurrent use; Manager serializes
+// updates to a single session.
+type Store interface {
+	Get(id string) (*Session, error)
+	Put(s *Session) error
+	Delete(id string) error
+}
+
+// MemoryStore keeps sessions in memory; they are lost on restart.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu sync.Mutex
+	m  map[string]*Session
+}
+
+// NewMemoryStore returns an empty store expiring sessions after ttl
+// without activity.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, m: map[string]*Session{}}
+}
+
+// Get returns a copy of the session, so callers can change it freely
+// until they Put it back.
+func (st *MemoryStore) Get(id string) (*Session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.m[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if time.Since(s.LastActivity) > st.ttl {
+		delete(st.m, id)
+		return nil, ErrNotFound
+	}
+	cp := *s
+	cp.Outcomes = append([]Outcome(nil), s.Outcomes...)
+	cp.Used = append([]bool(nil), s.Used...)
+	return &cp, nil
+}
+
+// Put stores the session and drops expired ones.
+func (st *MemoryStore) Put(s *Session) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := time.Now()
+	for k, v := range st.m {
+		if now.Sub(v.LastActivity) > st.ttl {
+			delete(st.m, k)
+		}
+	}
+	st.m[s.ID] = s
+	return nil
+}
+
+// Delete forgets a session.
+func (st *MemoryStore) Delete(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.m, id)
+	return nil
+}
@@ -0,0 +1,28 @@
+package snapshot
+
+import (
+	"flag"
+	"os"
+)
+
+// Flags are the archive options shared by every scraper command.
+type Flags struct {
+	Dir string
+}
+
+// RegisterFlags adds -archive to fs. It defaults to $SNAPSHOT_DIR, so
+// archiving is off unless that is set.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Dir, "archive", os.Getenv("SNAPSHOT_DIR"), `directory to archive every fetched page in, gzipped ("" = don't archive; default $SNAPSHOT_DIR)`)
+	return f
+}
+
+// Open opens the archive the flags describe, or returns nil when -archive
+// is empty.
+func (f *Flags) Open() (*Archive, error) {
+	if f.Dir == "" {
+		return nil, nil
+	}
+	return Open(f.Dir)
+}
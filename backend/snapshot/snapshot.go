@@ -0,0 +1,282 @@
+// Package snapshot archives the raw pages the scrapers fetch, gzipped and
+// keyed by URL and fetch time, so a suspicious team file can be traced to
+// either a changed page or a parser bug, and so past pages can be parsed
+// again without the network.
+//
+// A snapshot lives at <dir>/<host>/<url hash>/<time>-<body hash>.gz; the
+// gzip header carries the URL and the fetch time. A page identical to the
+// URL's latest snapshot isn't stored again.
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"futbol912.com/httpcache"
+)
+
+// ErrNotArchived is returned by a replay for a URL with no snapshot at or
+// before its time.
+var ErrNotArchived = errors.New("not in the snapshot archive")
+
+const timeLayout = "20060102T150405Z"
+
+// Archive is a directory of snapshots.
+type Archive struct {
+	Dir string
+}
+
+// Snapshot is one archived page.
+type Snapshot struct {
+	URL  string
+	At   time.Time
+	Path string
+}
+
+// Open returns the archive in dir, creating it if needed.
+func Open(dir string) (*Archive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Archive{Dir: dir}, nil
+}
+
+// urlDir is the directory holding rawURL's snapshots.
+func (a *Archive) urlDir(rawURL string) string {
+	host := "unknown"
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(a.Dir, host, hex.EncodeToString(sum[:8]))
+}
+
+// Save archives body as rawURL's page at time at, unless it's the same as
+// the latest snapshot of rawURL.
+func (a *Archive) Save(rawURL string, at time.Time, body []byte) error {
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:4])
+	dir := a.urlDir(rawURL)
+	list, err := a.List(rawURL)
+	if err != nil {
+		return err
+	}
+	if n := len(list); n > 0 && strings.HasSuffix(list[n-1].Path, "-"+digest+".gz") {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Comment = rawURL
+	zw.ModTime = at
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "snapshot-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	name := at.UTC().Format(timeLayout) + "-" + digest + ".gz"
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+// List returns rawURL's snapshots, oldest first.
+func (a *Archive) List(rawURL string) ([]Snapshot, error) {
+	dir := a.urlDir(rawURL)
+	names, err := filepath.Glob(filepath.Join(dir, "*.gz"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	list := make([]Snapshot, 0, len(names))
+	for _, p := range names {
+		stamp, _, _ := strings.Cut(filepath.Base(p), "-")
+		at, err := time.Parse(timeLayout, stamp)
+		if err != nil {
+			continue
+		}
+		list = append(list, Snapshot{URL: rawURL, At: at, Path: p})
+	}
+	return list, nil
+}
+
+// Latest returns rawURL's newest snapshot taken at or before t, or the
+// newest of all if t is zero.
+func (a *Archive) Latest(rawURL string, t time.Time) (Snapshot, bool, error) {
+	list, err := a.List(rawURL)
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		if t.IsZero() || !list[i].At.After(t) {
+			return list[i], true, nil
+		}
+	}
+	return Snapshot{}, false, nil
+}
+
+// Read returns the page in the snapshot file at path, and the URL and time
+// from its header. It works on a snapshot copied out of the archive, e.g.
+// into a test's testdata.
+func Read(path string) ([]byte, Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Snapshot{}, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, Snapshot{}, err
+	}
+	defer zr.Close()
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, Snapshot{}, err
+	}
+	return body, Snapshot{URL: zr.Comment, At: zr.ModTime.UTC(), Path: path}, nil
+}
+
+// Import files the snapshot at path, e.g. one copied from another machine's
+// archive, under the URL and time in its header.
+func (a *Archive) Import(path string) (Snapshot, error) {
+	body, snap, err := Read(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if snap.URL == "" || snap.At.IsZero() {
+		return Snapshot{}, fmt.Errorf("%s: no URL and time in the gzip header", path)
+	}
+	if err := a.Save(snap.URL, snap.At, body); err != nil {
+		return Snapshot{}, err
+	}
+	return snap, nil
+}
+
+// Recorder returns a RoundTripper that archives every 200 GET that next
+// returns. A nil archive returns next unchanged.
+func (a *Archive) Recorder(next http.RoundTripper) http.RoundTripper {
+	if a == nil {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recorder{archive: a, next: next}
+}
+
+// Client returns an http.Client going through cache with the recorder
+// under it, so only pages fetched from the network are archived: a page
+// the cache answers was archived, with its real fetch time, when it was
+// downloaded. Either of a and cache may be nil.
+func (a *Archive) Client(cache *httpcache.Cache, timeout time.Duration) *http.Client {
+	if cache == nil {
+		return &http.Client{Transport: a.Recorder(nil), Timeout: timeout}
+	}
+	return a.UnderCache(cache).Client(timeout)
+}
+
+// UnderCache returns a copy of cache that makes its requests through the
+// recorder. cache itself is left as is, so scrapers sharing one cache can
+// each take a copy. A nil cache stays nil.
+func (a *Archive) UnderCache(cache *httpcache.Cache) *httpcache.Cache {
+	if cache == nil || a == nil {
+		return cache
+	}
+	c := *cache
+	c.Transport = a.Recorder(cache.Transport)
+	return &c
+}
+
+type recorder struct {
+	archive *Archive
+	next    http.RoundTripper
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.archive.Save(req.URL.String(), time.Now(), body); err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// Replay returns a RoundTripper that answers GETs with the latest snapshot
+// taken at or before t (any time if t is zero) and never touches the
+// network. URLs without one fail with ErrNotArchived.
+func (a *Archive) Replay(t time.Time) http.RoundTripper {
+	return &replayer{archive: a, at: t}
+}
+
+type replayer struct {
+	archive *Archive
+	at      time.Time
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return nil, ErrNotArchived
+	}
+	snap, ok, err := r.archive.Latest(req.URL.String(), r.at)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotArchived
+	}
+	// The header is the snapshot's own record of what it holds; a file
+	// under the wrong URL's directory is not served for it.
+	body, hdr, err := Read(snap.Path)
+	if err != nil {
+		return nil, err
+	}
+	if hdr.URL != req.URL.String() {
+		return nil, fmt.Errorf("%s: snapshot of %s, not %s", snap.Path, hdr.URL, req.URL)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Last-Modified": {hdr.At.Format(http.TimeFormat)}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package snapshot
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"futbol912.com/httpcache"
+)
+
+// TestClientRecordsUnderCache checks that pages the cache answers aren't
+// archived: only a download from the network is, at its fetch time.
+func TestClientRecordsUnderCache(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		io.WriteString(w, "kader")
+	}))
+	defer srv.Close()
+
+	cache, err := httpcache.Open(t.TempDir(), time.Hour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(c *http.Client) {
+		t.Helper()
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	// Cached before the archive was in use.
+	get(cache.Client(time.Second))
+	get(archive.Client(cache, time.Second))
+	if list, err := archive.List(srv.URL); err != nil || len(list) != 0 {
+		t.Fatalf("archive has %d snapshots (%v) after a cache hit, want none", len(list), err)
+	}
+	if hits != 1 {
+		t.Fatalf("server hit %d times, want 1", hits)
+	}
+
+	// A download through the recorder is archived.
+	fresh, err := httpcache.Open(t.TempDir(), time.Hour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now().Add(-time.Second)
+	get(archive.Client(fresh, time.Second))
+	list, err := archive.List(srv.URL)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("archive has %d snapshots (%v) after a download, want 1", len(list), err)
+	}
+	if list[0].At.Before(before.Truncate(time.Second)) {
+		t.Errorf("snapshot time %v, want the download time", list[0].At)
+	}
+	if fresh.Transport != nil {
+		t.Error("Client changed the cache it was given")
+	}
+}
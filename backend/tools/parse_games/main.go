@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,9 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"futbol912.com/httpcache"
+	"futbol912.com/snapshot"
 )
 
 var candidates = []string{
@@ -22,8 +26,7 @@ var candidates = []string{
 	"https://cdn.playfootball.games/_astro/players.B8fSG47f.js",
 }
 
-func fetchURL(url string) (string, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
+func fetchURL(client *http.Client, url string) (string, error) {
 	resp, err := client.Get(url)
 	if err != nil {
 		return "", err
@@ -184,10 +187,25 @@ func sanitizeFilename(n string) string {
 }
 
 func main() {
+	cacheFlags := httpcache.RegisterFlags(flag.CommandLine)
+	archiveFlags := snapshot.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	cache, err := cacheFlags.Open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open http cache:", err)
+		os.Exit(1)
+	}
+	archive, err := archiveFlags.Open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open snapshot archive:", err)
+		os.Exit(1)
+	}
+	client := archive.Client(cache, 20*time.Second)
+
 	fmt.Println("parse_games: scanning candidate bundles for JSON-like game data")
 	for _, url := range candidates {
 		fmt.Printf("\nFetching: %s\n", url)
-		src, err := fetchURL(url)
+		src, err := fetchURL(client, url)
 		if err != nil {
 			fmt.Printf("  error fetching: %v\n", err)
 			continue
@@ -5,14 +5,19 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"regexp"
 	"strings"
+	"time"
+
+	"futbol912.com/httpcache"
+	"futbol912.com/snapshot"
 )
 
 func main() {
 	url := flag.String("url", "https://cdn.playfootball.games/_astro/players.C1MvKJw-.js", "players bundle url")
+	cacheFlags := httpcache.RegisterFlags(flag.CommandLine)
+	archiveFlags := snapshot.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
 	if !strings.HasPrefix(*url, "https://cdn.playfootball.games/_astro/players.") {
@@ -20,7 +25,18 @@ func main() {
 		os.Exit(2)
 	}
 
-	resp, err := http.Get(*url)
+	cache, err := cacheFlags.Open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cache error:", err)
+		os.Exit(1)
+	}
+	archive, err := archiveFlags.Open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "archive error:", err)
+		os.Exit(1)
+	}
+	client := archive.Client(cache, 30*time.Second)
+	resp, err := client.Get(*url)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "fetch error:", err)
 		os.Exit(1)
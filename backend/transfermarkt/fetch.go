@@ -0,0 +1,144 @@
+package transfermarkt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"futbol912.com/httpcache"
+)
+
+var userAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Safari/605.1.15",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0 Safari/537.36",
+}
+
+// RetryPolicy is the only retry loop around a page fetch: callers don't
+// retry on top of it, so backoffs don't compound.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // backoff before the second attempt, doubled after each
+	MaxDelay    time.Duration // cap on backoff and on Retry-After
+}
+
+// DefaultRetryPolicy is used when a league doesn't set max_attempts.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: 2 * time.Second, MaxDelay: 2 * time.Minute}
+
+// Backoff is the exponential delay with jitter after the given failed
+// attempt (0-based).
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := p.BaseDelay << attempt
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// StatusError is a non-200 response.
+type StatusError struct {
+	Code       int
+	Status     string
+	RetryAfter time.Duration // from the Retry-After header, 0 if absent
+}
+
+func (e *StatusError) Error() string { return e.Status }
+
+// retryable reports whether another attempt could succeed: network errors,
+// 429 and 5xx. Anything else (404, 403...) is final, and so is a page
+// missing from the cache offline.
+func retryable(err error) bool {
+	if errors.Is(err, httpcache.ErrNotCached) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code == http.StatusTooManyRequests || se.Code >= 500
+	}
+	return true
+}
+
+// parseRetryAfter reads a Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// fetchWithRetries GETs url with browser-like headers, waiting on the
+// shared limiter before each attempt the cache can't answer, and returns
+// how many attempts it took. A 429 or 503 with Retry-After pauses the
+// whole host for that long instead of the usual backoff.
+func (sc *Scraper) fetchWithRetries(url string) (string, int, error) {
+	policy := sc.Retry
+	if policy.MaxAttempts <= 0 {
+		policy = DefaultRetryPolicy
+	}
+	host := hostOf(url)
+
+	for attempt := 1; ; attempt++ {
+		if !sc.cache.Fresh(url) {
+			sc.Limiter.Wait(host)
+		}
+		body, err := sc.get(url)
+		if err == nil {
+			return body, attempt, nil
+		}
+		if !retryable(err) || attempt >= policy.MaxAttempts {
+			return "", attempt, fmt.Errorf("%s: %w", url, err)
+		}
+
+		var se *StatusError
+		if errors.As(err, &se) && se.RetryAfter > 0 {
+			wait := min(se.RetryAfter, policy.MaxDelay)
+			if sc.Limiter != nil {
+				sc.Limiter.Pause(host, wait)
+			} else {
+				time.Sleep(wait)
+			}
+			continue
+		}
+		time.Sleep(policy.Backoff(attempt - 1))
+	}
+}
+
+func (sc *Scraper) get(url string) (string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	// randomize UA
+	req.Header.Set("User-Agent", userAgents[rand.Intn(len(userAgents))])
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Referer", "https://www.google.com/")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	resp, err := sc.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		se := &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			se.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+		return "", se
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
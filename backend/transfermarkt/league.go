@@ -0,0 +1,225 @@
+package transfermarkt
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"futbol912.com/httpcache"
+	"futbol912.com/leagues"
+	"futbol912.com/snapshot"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// CompetitionURL is the league's overview page listing its clubs.
+func CompetitionURL(l leagues.League) string {
+	return fmt.Sprintf("https://%s/%s/startseite/wettbewerb/%s/saison_id/%d", l.Host, l.Path, l.Code, l.Season)
+}
+
+// Team is a club found on a competition page.
+type Team struct {
+	Slug string // file name without extension, e.g. "fc-arsenal"
+	URL  string // squad (kader) page for the league's season
+}
+
+// Scraper fetches and parses pages for one league.
+type Scraper struct {
+	League leagues.League
+	Client *http.Client
+	Retry  RetryPolicy
+	// Limiter paces requests per host; share one between the scrapers of
+	// every league. Nil means no limit.
+	Limiter *Limiter
+
+	cache *httpcache.Cache
+}
+
+// UseCache sends the scraper's requests through c. Pages c can answer
+// without a request don't wait on the Limiter.
+func (sc *Scraper) UseCache(c *httpcache.Cache) {
+	sc.cache = c
+	if c != nil {
+		sc.Client.Transport = c
+	}
+}
+
+// UseArchive keeps a snapshot of every page the scraper fetches from the
+// network in a. Call it after UseCache: the recorder goes under the cache,
+// so pages the cache answers aren't archived again as if just fetched.
+func (sc *Scraper) UseArchive(a *snapshot.Archive) {
+	if sc.cache != nil {
+		sc.UseCache(a.UnderCache(sc.cache))
+		return
+	}
+	sc.Client.Transport = a.Recorder(sc.Client.Transport)
+}
+
+// NewScraper returns a scraper for l with the league's retry count and no
+// rate limit.
+func NewScraper(l leagues.League) *Scraper {
+	retry := DefaultRetryPolicy
+	if l.MaxAttempts > 0 {
+		retry.MaxAttempts = l.MaxAttempts
+	}
+	return &Scraper{
+		League: l,
+		Client: &http.Client{Timeout: 30 * time.Second},
+		Retry:  retry,
+	}
+}
+
+var reClubLink = regexp.MustCompile(`(?i)(?:/([a-z0-9\-]+?)/)?startseite/verein/(\d+)`)
+
+// DiscoverTeams reads the competition page and returns the league's clubs
+// sorted by slug.
+func (sc *Scraper) DiscoverTeams() ([]Team, error) {
+	body, _, err := sc.fetchWithRetries(CompetitionURL(sc.League))
+	if err != nil {
+		return nil, err
+	}
+	return sc.parseTeams(body)
+}
+
+// parseTeams collects the club links of a competition page. Squad URLs
+// always use the link's slug; file names follow the league's FileNames.
+func (sc *Scraper) parseTeams(body string) ([]Team, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// A club is linked several times (crest, name); keep one entry per club
+	// ID and prefer a link with text over the crest's title.
+	type club struct{ slug, text, title string }
+	clubs := map[string]*club{}
+	doc.Find("a").Each(func(i int, s *goquery.Selection) {
+		href, ok := s.Attr("href")
+		if !ok {
+			return
+		}
+		m := reClubLink.FindStringSubmatch(href)
+		if len(m) < 3 {
+			return
+		}
+		slug := m[1]
+		if slug == "" {
+			slug = canonicalFilename(s.Text())
+		}
+		slug = sanitizeFilename(slug)
+		if slug == "" || slug == "team" {
+			return
+		}
+		c := clubs[m[2]]
+		if c == nil {
+			c = &club{slug: slug}
+			clubs[m[2]] = c
+		}
+		if c.text == "" {
+			c.text = linkTextFilename(s.Text())
+		}
+		if title, _ := s.Attr("title"); c.title == "" {
+			c.title = linkTextFilename(title)
+		}
+	})
+
+	links := map[string]string{} // file name -> squad URL
+	for id, c := range clubs {
+		name := c.slug
+		if sc.League.FileNames == leagues.FileNamesLinkText {
+			switch {
+			case c.text != "":
+				name = c.text
+			case c.title != "":
+				name = c.title
+			}
+		}
+		links[name] = sc.SquadURL(c.slug, id)
+	}
+
+	teams := make([]Team, 0, len(links))
+	for name, url := range links {
+		teams = append(teams, Team{Slug: name, URL: url})
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Slug < teams[j].Slug })
+	return teams, nil
+}
+
+// SquadURL builds the kader page of a club for the league's season.
+func (sc *Scraper) SquadURL(slug, clubID string) string {
+	return fmt.Sprintf("https://%s/%s/kader/verein/%s/saison_id/%d", sc.League.Host, slug, clubID, sc.League.Season)
+}
+
+// sanitizeFilename converts a club name or slug into a safe filename like "fc-barcelona"
+func sanitizeFilename(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	// replace spaces and slashes
+	s = strings.ReplaceAll(s, " ", "-")
+	s = strings.ReplaceAll(s, "/", "-")
+	// remove any chars except letters, numbers, dash
+	re := regexp.MustCompile(`[^a-z0-9\-]`)
+	s = re.ReplaceAllString(s, "")
+	if s == "" {
+		s = "team"
+	}
+	return s
+}
+
+// linkTextFilename names a team file from the club's link text the way the
+// original Bundesliga and Ligue 1 scrapers did, so their existing files keep
+// their names: "Bayern Munich" -> "bayern-munich", "AS Mónaco" -> "as-mnaco".
+func linkTextFilename(text string) string {
+	s := strings.ToLower(strings.TrimSpace(text))
+	s = strings.NewReplacer("\u00e1", "a", "\u00e0", "a", "\u00e9", "e", "\u00f6", "o", " ", "-").Replace(s)
+	s = reNotFilename.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "--", "-")
+	return strings.Trim(s, "-")
+}
+
+var reNotFilename = regexp.MustCompile(`[^a-z0-9\-]`)
+
+// canonicalFilename attempts to normalize human club names into compact filenames.
+// Examples:
+//
+//	"FC Barcelona" -> "barcelona"
+//	"Real Madrid CF" -> "real-madrid"
+//	"RC Celta de Vigo" -> "celta-de-vigo"
+func canonicalFilename(raw string) string {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	// remove commas, parentheses
+	s = strings.ReplaceAll(s, ",", "")
+	s = strings.ReplaceAll(s, "(", "")
+	s = strings.ReplaceAll(s, ")", "")
+	// tokenize
+	parts := strings.Fields(s)
+	if len(parts) == 0 {
+		return sanitizeFilename(raw)
+	}
+	// drop common prefix tokens (e.g., "fc", "ud", "rc", "rcd")
+	prefixDrop := map[string]bool{"fc": true, "ud": true, "rc": true, "rcd": true, "deportivo": true}
+	for len(parts) > 0 {
+		p := strings.Trim(parts[0], "- .")
+		if prefixDrop[p] {
+			parts = parts[1:]
+		} else {
+			break
+		}
+	}
+	// drop trailing club-type tokens (e.g., "cf", "fc")
+	suffixDrop := map[string]bool{"cf": true, "fc": true}
+	for len(parts) > 0 {
+		p := strings.Trim(parts[len(parts)-1], "- .")
+		if suffixDrop[p] {
+			parts = parts[:len(parts)-1]
+		} else {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return sanitizeFilename(raw)
+	}
+	cand := strings.Join(parts, "-")
+	return sanitizeFilename(cand)
+}
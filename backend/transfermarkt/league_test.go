@@ -0,0 +1,52 @@
+package transfermarkt
+
+import (
+	"testing"
+
+	"futbol912.com/leagues"
+)
+
+const competitionPage = `<table>
+<tr><td><a href="/fc-augsburg/startseite/verein/167/saison_id/2025" title="FC Augsburg"></a></td>
+<td><a href="/fc-augsburg/startseite/verein/167/saison_id/2025">Augsburgo</a></td></tr>
+<tr><td><a href="/borussia-monchengladbach/startseite/verein/18/saison_id/2025">Borussia Mönchengladbach</a></td></tr>
+<tr><td><a href="/as-monaco/startseite/verein/162/saison_id/2025">AS Mónaco</a></td></tr>
+</table>`
+
+func TestParseTeamsFileNames(t *testing.T) {
+	tests := []struct {
+		fileNames string
+		want      map[string]string // file name -> squad URL
+	}{
+		{"", map[string]string{
+			"fc-augsburg":              "https://www.transfermarkt.es/fc-augsburg/kader/verein/167/saison_id/2025",
+			"borussia-monchengladbach": "https://www.transfermarkt.es/borussia-monchengladbach/kader/verein/18/saison_id/2025",
+			"as-monaco":                "https://www.transfermarkt.es/as-monaco/kader/verein/162/saison_id/2025",
+		}},
+		{leagues.FileNamesLinkText, map[string]string{
+			"augsburgo":                "https://www.transfermarkt.es/fc-augsburg/kader/verein/167/saison_id/2025",
+			"borussia-monchengladbach": "https://www.transfermarkt.es/borussia-monchengladbach/kader/verein/18/saison_id/2025",
+			"as-mnaco":                 "https://www.transfermarkt.es/as-monaco/kader/verein/162/saison_id/2025",
+		}},
+	}
+	for _, tt := range tests {
+		sc := NewScraper(leagues.League{Host: "www.transfermarkt.es", Season: 2025, FileNames: tt.fileNames})
+		teams, err := sc.parseTeams(competitionPage)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, tm := range teams {
+			got[tm.Slug] = tm.URL
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("file_names %q: got %v, want %v", tt.fileNames, got, tt.want)
+			continue
+		}
+		for name, url := range tt.want {
+			if got[name] != url {
+				t.Errorf("file_names %q: %s = %q, want %q", tt.fileNames, name, got[name], url)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package transfermarkt
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket per host, shared by every scraper that talks
+// to that host, so running teams concurrently doesn't raise the request
+// rate. A host can also be paused, e.g. for a Retry-After.
+type Limiter struct {
+	rate  float64 // tokens per second; <= 0 disables limiting
+	burst float64
+
+	mu    sync.Mutex
+	hosts map[string]*bucket
+}
+
+type bucket struct {
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewLimiter allows rate requests per second per host, with bursts of up
+// to burst requests.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{rate: rate, burst: float64(max(burst, 1)), hosts: map[string]*bucket{}}
+}
+
+// Wait blocks until a request to host may be sent.
+func (l *Limiter) Wait(host string) {
+	if l == nil {
+		return
+	}
+	for {
+		d := l.reserve(host)
+		if d <= 0 {
+			return
+		}
+		time.Sleep(d)
+	}
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before
+// trying again.
+func (l *Limiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	b := l.bucket(host, now)
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// Pause holds every request to host for d, and empties its bucket so the
+// host isn't hit with a burst when the pause ends.
+func (l *Limiter) Pause(host string, d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	b := l.bucket(host, now)
+	if until := now.Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	b.tokens = 0
+	b.last = b.pausedUntil
+}
+
+func (l *Limiter) bucket(host string, now time.Time) *bucket {
+	b, ok := l.hosts[host]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.hosts[host] = b
+	}
+	return b
+}
+
+// hostOf returns the host of rawURL, or rawURL itself if it doesn't parse.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Host
+}
@@ -0,0 +1,84 @@
+package transfermarkt
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Job is one team to scrape and the directory its JSON goes to.
+type Job struct {
+	Scraper *Scraper
+	Team    Team
+	OutDir  string
+}
+
+// Path is the team file the job writes.
+func (j Job) Path() string {
+	return filepath.Join(j.OutDir, j.Team.Slug+".json")
+}
+
+// JobResult is the outcome of a job. Err covers both the scrape and the
+// save.
+type JobResult struct {
+	Job
+	Players  int
+	Attempts int // fetch attempts, from the scraper's retry policy
+	Err      error
+	Elapsed  time.Duration
+}
+
+// Orchestrator scrapes teams concurrently. Pacing is left to the scrapers'
+// shared Limiter, so Concurrency only bounds how many requests can wait
+// on it at once.
+type Orchestrator struct {
+	Concurrency int
+	// Done, if set, is called as each job finishes, from the worker that
+	// ran it; calls don't overlap.
+	Done func(JobResult)
+}
+
+// Run scrapes and saves every job and returns the results in job order.
+func (o *Orchestrator) Run(jobs []Job) []JobResult {
+	results := make([]JobResult, len(jobs))
+	next := make(chan int)
+	var (
+		wg     sync.WaitGroup
+		doneMu sync.Mutex
+	)
+	for w := 0; w < max(o.Concurrency, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				res := runJob(jobs[i])
+				results[i] = res
+				if o.Done != nil {
+					doneMu.Lock()
+					o.Done(res)
+					doneMu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+func runJob(j Job) JobResult {
+	start := time.Now()
+	res := JobResult{Job: j}
+	players, attempts, err := j.Scraper.scrapeClubRoster(j.Team.URL)
+	res.Attempts = attempts
+	if err == nil {
+		res.Players = len(players)
+		err = SaveTeamJSON(j.Team.Slug, players, j.Path())
+	}
+	res.Err = err
+	res.Elapsed = time.Since(start)
+	return res
+}
@@ -0,0 +1,107 @@
+package transfermarkt
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	reAgeParen = regexp.MustCompile(`\((\d{1,2})\)`)
+	reNumber   = regexp.MustCompile(`[\d.,]+`)
+)
+
+// dateLayouts are the date formats used by the .com and .es sites.
+var dateLayouts = []string{
+	"02.01.2006",
+	"02/01/2006",
+	"Jan 2, 2006",
+	"2006-01-02",
+}
+
+// Normalize fills the typed fields from the raw strings. Fields are
+// recognised by content rather than by column, because the .es short
+// layout shifts the market value into Contract and shows a birthdate in Age.
+func (p *Player) Normalize() {
+	p.MarketValueEUR, p.AgeYears, p.Birthdate, p.ContractExpires = 0, 0, "", ""
+	for _, raw := range []string{p.Age, p.Contract, p.MarketValue} {
+		raw = strings.TrimSpace(raw)
+		switch {
+		case raw == "" || raw == "-":
+		case strings.Contains(raw, "€"):
+			if p.MarketValueEUR == 0 {
+				p.MarketValueEUR = ParseMarketValue(raw)
+			}
+		case reAgeParen.MatchString(raw) || isAge(raw):
+			if p.AgeYears == 0 {
+				p.AgeYears, p.Birthdate = ParseAge(raw)
+			}
+		default:
+			if d := ParseDate(raw); d != "" && p.ContractExpires == "" {
+				p.ContractExpires = d
+			}
+		}
+	}
+}
+
+func isAge(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && n < 100
+}
+
+// ParseAge accepts "27" and "27/03/1986 (39)", returning the age and, when
+// shown, the birthdate as YYYY-MM-DD.
+func ParseAge(s string) (int, string) {
+	s = strings.TrimSpace(s)
+	if m := reAgeParen.FindStringSubmatchIndex(s); m != nil {
+		n, _ := strconv.Atoi(s[m[2]:m[3]])
+		return n, ParseDate(s[:m[0]])
+	}
+	n, _ := strconv.Atoi(s)
+	return n, ""
+}
+
+// ParseDate accepts "30.06.2029", "30/06/2029" and "Jun 30, 2029", returning
+// YYYY-MM-DD or "" when it can't.
+func ParseDate(s string) string {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+// ParseMarketValue accepts "€75.00m", "€500k", "20,00 mill. €" and "300 mil €",
+// returning euros.
+func ParseMarketValue(s string) int64 {
+	s = strings.ToLower(strings.TrimSpace(s))
+	num := reNumber.FindString(s)
+	if num == "" {
+		return 0
+	}
+	if strings.Contains(s, "mil") {
+		// Spanish format uses comma decimals and "mill."/"mil" units.
+		num = strings.ReplaceAll(num, ".", "")
+		num = strings.ReplaceAll(num, ",", ".")
+	} else {
+		num = strings.ReplaceAll(num, ",", "")
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	switch {
+	case strings.Contains(s, "mill") || strings.HasSuffix(s, "m"):
+		v *= 1e6
+	case strings.Contains(s, "mil") || strings.HasSuffix(s, "k"):
+		v *= 1e3
+	case strings.Contains(s, "bn"):
+		v *= 1e9
+	}
+	// 4.10 * 1e6 is 4099999.9999...; truncating would lose a euro.
+	return int64(math.Round(v))
+}
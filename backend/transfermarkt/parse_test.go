@@ -0,0 +1,30 @@
+package transfermarkt
+
+import "testing"
+
+func TestParseMarketValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"€75.00m", 75_000_000},
+		{"€4.10m", 4_100_000},
+		{"€0.30m", 300_000},
+		{"€1.15m", 1_150_000},
+		{"€12.35m", 12_350_000},
+		{"€500k", 500_000},
+		{"€1.20bn", 1_200_000_000},
+		{"4,10 mill. €", 4_100_000},
+		{"20,00 mill. €", 20_000_000},
+		{"0,55 mill. €", 550_000},
+		{"1,15 mill. €", 1_150_000},
+		{"300 mil €", 300_000},
+		{"-", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := ParseMarketValue(tt.in); got != tt.want {
+			t.Errorf("ParseMarketValue(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package transfermarkt
+
+import "strings"
+
+// Position groups stored in Player.PositionGroup.
+const (
+	GroupGoalkeeper = "GK"
+	GroupDefender   = "DEF"
+	GroupMidfielder = "MID"
+	GroupForward    = "FWD"
+)
+
+// positionGroups maps the detailed positions Transfermarkt shows, on both
+// the .com and .es sites, to their group.
+var positionGroups = map[string]string{
+	"goalkeeper": GroupGoalkeeper,
+	"portero":    GroupGoalkeeper,
+
+	"defender":          GroupDefender,
+	"centre-back":       GroupDefender,
+	"left-back":         GroupDefender,
+	"right-back":        GroupDefender,
+	"sweeper":           GroupDefender,
+	"defensa":           GroupDefender,
+	"defensa central":   GroupDefender,
+	"lateral izquierdo": GroupDefender,
+	"lateral derecho":   GroupDefender,
+	"líbero":            GroupDefender,
+
+	"midfield":             GroupMidfielder,
+	"defensive midfield":   GroupMidfielder,
+	"central midfield":     GroupMidfielder,
+	"attacking midfield":   GroupMidfielder,
+	"left midfield":        GroupMidfielder,
+	"right midfield":       GroupMidfielder,
+	"centrocampista":       GroupMidfielder,
+	"medio":                GroupMidfielder,
+	"pivote":               GroupMidfielder,
+	"mediocentro":          GroupMidfielder,
+	"mediocentro ofensivo": GroupMidfielder,
+	"mediapunta":           GroupMidfielder,
+	"interior izquierdo":   GroupMidfielder,
+	"interior derecho":     GroupMidfielder,
+
+	"attack":            GroupForward,
+	"forward":           GroupForward,
+	"centre-forward":    GroupForward,
+	"second striker":    GroupForward,
+	"left winger":       GroupForward,
+	"right winger":      GroupForward,
+	"delantero":         GroupForward,
+	"delantero centro":  GroupForward,
+	"segundo delantero": GroupForward,
+	"extremo izquierdo": GroupForward,
+	"extremo derecho":   GroupForward,
+}
+
+// PositionGroup returns GK, DEF, MID or FWD for a detailed position such as
+// "Centre-Back" or "Extremo derecho", or "" when it is not recognised.
+func PositionGroup(position string) string {
+	p := strings.ToLower(strings.TrimSpace(position))
+	if p == "" {
+		return ""
+	}
+	if g, ok := positionGroups[p]; ok {
+		return g
+	}
+	// Unlisted variants: match on keywords, midfield before defence so
+	// "mediocentro" doesn't fall into "central".
+	switch {
+	case strings.Contains(p, "goalkeeper") || strings.Contains(p, "portero"):
+		return GroupGoalkeeper
+	case strings.Contains(p, "midfield") || strings.Contains(p, "medio") || strings.Contains(p, "interior"):
+		return GroupMidfielder
+	case strings.Contains(p, "back") || strings.Contains(p, "defen") || strings.Contains(p, "lateral"):
+		return GroupDefender
+	case strings.Contains(p, "wing") || strings.Contains(p, "forward") || strings.Contains(p, "striker") ||
+		strings.Contains(p, "extremo") || strings.Contains(p, "delantero"):
+		return GroupForward
+	}
+	return ""
+}
@@ -1,15 +1,16 @@
-package premier
+// Package transfermarkt scrapes club squads from Transfermarkt. A Scraper is
+// configured per league from the registry in package leagues and shares one
+// roster parser, so a parser fix applies to every league at once.
+package transfermarkt
 
 import (
 	"encoding/json"
-	"errors"
 	"io"
-	"math/rand"
-	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -19,58 +20,46 @@ type Player struct {
 	ID            string   `json:"id"`
 	Name          string   `json:"name"`
 	ShirtNumber   string   `json:"number,omitempty"`
+	Position      string   `json:"position,omitempty"`       // as shown by the site, e.g. "Centre-Back"
+	PositionGroup string   `json:"position_group,omitempty"` // GK, DEF, MID or FWD
 	Age           string   `json:"age,omitempty"`
 	Nationalities []string `json:"nationalities"`
 	Contract      string   `json:"contract,omitempty"`
 	MarketValue   string   `json:"market_value,omitempty"`
 	FlagURL       string   `json:"flag_url,omitempty"`
 	PhotoURL      string   `json:"photo_url,omitempty"`
-}
-
-// fetchWithRetries performs a GET with polite retries, backoff and stealthy headers.
-func fetchWithRetries(url string, maxAttempts int) (string, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
-	uas := []string{
-		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36",
-		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Safari/605.1.15",
-		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0 Safari/537.36",
-	}
-	var lastErr error
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		req, _ := http.NewRequest("GET", url, nil)
-		// randomize UA
-		req.Header.Set("User-Agent", uas[rand.Intn(len(uas))])
-		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
-		req.Header.Set("Referer", "https://www.google.com/")
-		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = err
-		} else {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			if resp.StatusCode == http.StatusOK {
-				return string(body), nil
-			}
-			lastErr = errors.New(resp.Status)
-		}
 
-		// exponential backoff + jitter
-		backoff := time.Duration((1 << attempt)) * time.Second
-		jitter := time.Duration(rand.Intn(1500)) * time.Millisecond
-		time.Sleep(backoff + jitter)
-	}
-	return "", lastErr
+	// Typed fields parsed from the raw strings above, which are kept for
+	// display. Zero values mean the value was missing; see Normalize.
+	MarketValueEUR  int64  `json:"market_value_eur,omitempty"`
+	AgeYears        int    `json:"age_years,omitempty"`
+	Birthdate       string `json:"birthdate,omitempty"`        // YYYY-MM-DD
+	ContractExpires string `json:"contract_expires,omitempty"` // YYYY-MM-DD
 }
 
 // ScrapeClubRoster fetches a Transfermarkt club roster page and extracts players.
-func ScrapeClubRoster(url string) ([]Player, error) {
-	body, err := fetchWithRetries(url, 5)
+func (sc *Scraper) ScrapeClubRoster(url string) ([]Player, error) {
+	players, _, err := sc.scrapeClubRoster(url)
+	return players, err
+}
+
+// scrapeClubRoster is ScrapeClubRoster that also reports the fetch
+// attempts.
+func (sc *Scraper) scrapeClubRoster(url string) ([]Player, int, error) {
+	body, attempts, err := sc.fetchWithRetries(url)
 	if err != nil {
-		return nil, err
+		return nil, attempts, err
 	}
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	players, err := ParseClubRoster(strings.NewReader(body))
+	return players, attempts, err
+}
+
+// ParseClubRoster extracts the players of a club roster (kader) page, from
+// either the .com or the .es site. Rows of the nested inline tables are
+// parsed too and yield partial duplicates of their player; SaveTeamJSON
+// merges them by ID.
+func ParseClubRoster(r io.Reader) ([]Player, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +101,15 @@ func ScrapeClubRoster(url string) ([]Player, error) {
 		contract := strings.TrimSpace(s.Children().Eq(4).Text())
 		market := strings.TrimSpace(s.Children().Eq(5).Text())
 
+		// The position sits in the second row of the inline table under the
+		// name; the shirt number cell repeats it in its title.
+		position := strings.TrimSpace(s.Find("td.posrela table.inline-table tr").Eq(1).Find("td").Last().Text())
+		if position == "" {
+			if t, ok := s.Find("td.rueckennummer").Attr("title"); ok {
+				position = strings.TrimSpace(t)
+			}
+		}
+
 		var nats []string
 		var flagURL string
 		var photoURL string
@@ -123,48 +121,19 @@ func ScrapeClubRoster(url string) ([]Player, error) {
 				photoURL = strings.TrimSpace(v)
 			}
 		}
-		// Prefer the flag in the 4th direct child TD (index 3) which usually contains the country flag
+		// Every flag in the 4th direct child TD (index 3) is a nationality:
+		// dual nationals get one image per country. The first is the
+		// player's flag.
 		if cell := s.Children().Eq(3); cell.Length() > 0 {
-			if img := cell.Find("img").First(); img.Length() > 0 {
-				// capture flag URL from data-src or src
-				if v, ok := img.Attr("data-src"); ok && strings.TrimSpace(v) != "" {
-					flagURL = strings.TrimSpace(v)
-				} else if v, ok := img.Attr("src"); ok {
-					flagURL = strings.TrimSpace(v)
+			cell.Find("img").Each(func(_ int, img *goquery.Selection) {
+				if flagURL == "" {
+					flagURL = imgSrc(img)
 				}
 				// prefer alt, then title (some pages use alt for the country)
-				if a, ok := img.Attr("alt"); ok {
-					a = strings.TrimSpace(a)
-					if a != "" {
-						nats = append(nats, a)
-					}
-				}
-				if len(nats) == 0 {
-					if t, ok := img.Attr("title"); ok {
-						t = strings.TrimSpace(t)
-						if t != "" {
-							nats = append(nats, t)
-						}
-					}
+				if n := flagName(img, "alt", "title"); n != "" {
+					nats = appendUnique(nats, n)
 				}
-				// also check src for flag patterns and fallback to alt or filename
-				if len(nats) == 0 {
-					if src, ok := img.Attr("src"); ok {
-						src = strings.ToLower(src)
-						if strings.Contains(src, "flagge") || strings.Contains(src, "tmssl") || strings.Contains(src, "flag") {
-							if a, ok := img.Attr("alt"); ok && strings.TrimSpace(a) != "" {
-								nats = append(nats, strings.TrimSpace(a))
-							} else {
-								parts := strings.Split(src, "/")
-								last := parts[len(parts)-1]
-								if last != "" {
-									nats = append(nats, last)
-								}
-							}
-						}
-					}
-				}
-			}
+			})
 		}
 
 		// fallback: inspect any <img> in the row and prefer those that look like a flag
@@ -183,25 +152,10 @@ func ScrapeClubRoster(url string) ([]Player, error) {
 				}
 				// capture flagURL if not set
 				if flagURL == "" {
-					if v, ok := img.Attr("data-src"); ok && strings.TrimSpace(v) != "" {
-						flagURL = strings.TrimSpace(v)
-					} else if v, ok := img.Attr("src"); ok {
-						flagURL = strings.TrimSpace(v)
-					}
-				}
-				if t, ok := img.Attr("title"); ok {
-					t = strings.TrimSpace(t)
-					if t != "" {
-						nats = append(nats, t)
-						return
-					}
+					flagURL = imgSrc(img)
 				}
-				if a, ok := img.Attr("alt"); ok {
-					a = strings.TrimSpace(a)
-					if a != "" {
-						nats = append(nats, a)
-						return
-					}
+				if n := flagName(img, "title", "alt"); n != "" {
+					nats = appendUnique(nats, n)
 				}
 			})
 		}
@@ -210,10 +164,9 @@ func ScrapeClubRoster(url string) ([]Player, error) {
 		if len(nats) == 0 {
 			if html, err := s.Html(); err == nil {
 				reFlag := regexp.MustCompile(`(?i)<img[^>]+(?:flagge|flaggenrahmen|images/flagge|tmssl)[^>]+title\s*=\s*"([^"]+)"`)
-				if m := reFlag.FindStringSubmatch(html); len(m) > 1 {
-					val := strings.TrimSpace(m[1])
-					if val != "" {
-						nats = append(nats, val)
+				for _, m := range reFlag.FindAllStringSubmatch(html, -1) {
+					if val := strings.TrimSpace(m[1]); val != "" && !reImageFile.MatchString(val) {
+						nats = appendUnique(nats, val)
 					}
 				}
 				// also try to extract src for flag url
@@ -229,22 +182,62 @@ func ScrapeClubRoster(url string) ([]Player, error) {
 			nats = []string{}
 		}
 
-		players = append(players, Player{
+		p := Player{
 			ID:            id,
 			Name:          name,
 			ShirtNumber:   number,
+			Position:      position,
+			PositionGroup: PositionGroup(position),
 			Age:           age,
 			Nationalities: nats,
 			Contract:      contract,
 			MarketValue:   market,
 			FlagURL:       flagURL,
 			PhotoURL:      photoURL,
-		})
+		}
+		p.Normalize()
+		players = append(players, p)
 	})
 
 	return players, nil
 }
 
+// reImageFile matches image file names and paths, which some flags carry
+// in place of a country name ("9.png?lm=1520611569").
+var reImageFile = regexp.MustCompile(`(?i)(/|\.(png|jpe?g|gif|svg|webp)(\?|$))`)
+
+// flagName is the country a flag image names in the first of attrs that
+// is set. Image file names are not countries and give "".
+func flagName(img *goquery.Selection, attrs ...string) string {
+	for _, attr := range attrs {
+		if v, ok := img.Attr(attr); ok {
+			if v = strings.TrimSpace(v); v != "" && !reImageFile.MatchString(v) {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// imgSrc is an image's URL, preferring data-src over the placeholder lazy
+// images keep in src.
+func imgSrc(img *goquery.Selection) string {
+	if v, ok := img.Attr("data-src"); ok && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	v, _ := img.Attr("src")
+	return strings.TrimSpace(v)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
 // SavePlayersIndex writes a simple map[id]player JSON file to path.
 func SavePlayersIndex(players []Player, path string) error {
 	index := map[string]Player{}
@@ -279,6 +272,10 @@ func SavePlayersIndex(players []Player, path string) error {
 			if existing.ShirtNumber == "" && p.ShirtNumber != "" {
 				existing.ShirtNumber = p.ShirtNumber
 			}
+			if existing.Position == "" && p.Position != "" {
+				existing.Position = p.Position
+				existing.PositionGroup = p.PositionGroup
+			}
 			if existing.Age == "" && p.Age != "" {
 				existing.Age = p.Age
 			}
@@ -288,21 +285,14 @@ func SavePlayersIndex(players []Player, path string) error {
 			if existing.MarketValue == "" && p.MarketValue != "" {
 				existing.MarketValue = p.MarketValue
 			}
+			existing.Normalize()
 			index[key] = existing
 		} else {
 			index[key] = p
 		}
 	}
 
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(map[string]any{"players": index})
+	return writeJSON(path, map[string]any{"players": index})
 }
 
 // SaveTeamJSON writes a team-specific JSON file with team name and players array.
@@ -323,6 +313,10 @@ func SaveTeamJSON(teamName string, players []Player, path string) error {
 			if ex.ShirtNumber == "" {
 				ex.ShirtNumber = p.ShirtNumber
 			}
+			if ex.Position == "" {
+				ex.Position = p.Position
+				ex.PositionGroup = p.PositionGroup
+			}
 			if ex.Age == "" {
 				ex.Age = p.Age
 			}
@@ -338,6 +332,7 @@ func SaveTeamJSON(teamName string, players []Player, path string) error {
 			if ex.PhotoURL == "" {
 				ex.PhotoURL = p.PhotoURL
 			}
+			ex.Normalize()
 			// union nationalities
 			seen := map[string]bool{}
 			for _, n := range ex.Nationalities {
@@ -358,17 +353,42 @@ func SaveTeamJSON(teamName string, players []Player, path string) error {
 	for _, v := range merged {
 		outPlayers = append(outPlayers, v)
 	}
+	// Sorted so an unchanged roster rewrites the same bytes.
+	sort.Slice(outPlayers, func(i, j int) bool {
+		if outPlayers[i].Name != outPlayers[j].Name {
+			return outPlayers[i].Name < outPlayers[j].Name
+		}
+		return outPlayers[i].ID < outPlayers[j].ID
+	})
 
-	out := map[string]any{
+	return writeJSON(path, map[string]any{
 		"team":    teamName,
 		"players": outPlayers,
-	}
-	f, err := os.Create(path)
+	})
+}
+
+// writeJSON writes v as indented JSON through a temp file in the same
+// directory and a rename, so the catalog watcher never reads a half
+// written team file.
+func writeJSON(path string, v any) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	enc := json.NewEncoder(f)
+	defer os.Remove(tmp.Name())
+	// CreateTemp makes the file 0600; team files are read by other users.
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	enc := json.NewEncoder(tmp)
 	enc.SetIndent("", "  ")
-	return enc.Encode(out)
+	if err := enc.Encode(v); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
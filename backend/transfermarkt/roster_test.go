@@ -0,0 +1,222 @@
+package transfermarkt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"futbol912.com/snapshot"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// readPage returns a saved kader page from testdata: name.html, or the
+// snapshot name.html.gz copied from the scraper's archive.
+func readPage(t *testing.T, name string) []byte {
+	t.Helper()
+	path := filepath.Join("testdata", name+".html")
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		body, _, err = snapshot.Read(path + ".gz")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// TestParseClubRosterGolden parses every saved kader page in testdata and
+// compares the players with the matching .golden.json file. A page can
+// also be a snapshot copied from the scraper's archive (kader_*.html.gz),
+// so a page that once broke the parser stays covered. Run with -update
+// after a deliberate parser change and review the diff.
+func TestParseClubRosterGolden(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "kader_*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := filepath.Glob(filepath.Join("testdata", "kader_*.html.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages = append(pages, snapshots...)
+	if len(pages) == 0 {
+		t.Fatal("no kader pages in testdata")
+	}
+	for _, page := range pages {
+		name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(page), ".gz"), ".html")
+		t.Run(name, func(t *testing.T) {
+			players, err := ParseClubRoster(bytes.NewReader(readPage(t, name)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(players, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", name+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("players differ from %s (run go test -update and review the diff)\ngot:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+// Nationality cells as saved in testdata: David Raya's single flag on the
+// .com page and David Alaba's two on the archived .es page.
+const (
+	rayaFlagSrc = "https://tmssl.akamaized.net//images/flagge/verysmall/157.png?lm=1520611569"
+	rayaNatCell = `<td class="zentriert"><img src="` + rayaFlagSrc + `" title="Spain" alt="Spain" class="flaggenrahmen" /></td>`
+	alabaFlag1  = "https://tmssl.akamaized.net//images/flagge/verysmall/127.png?lm=1520611569"
+)
+
+// TestParseClubRosterNationality covers the three nationality sources in
+// order: the images in the nationality cell, any flag image in the row,
+// and a regex over the row's raw HTML. Each case edits a saved kader page
+// to exercise one source, then checks one player's row.
+func TestParseClubRosterNationality(t *testing.T) {
+	// moveFlag empties Raya's nationality cell and puts img in his player
+	// cell instead, after the inline table.
+	moveFlag := func(img string) []string {
+		return []string{
+			rayaNatCell, `<td class="zentriert">-</td>`,
+			"<tr><td>Goalkeeper</td></tr>\n</table>\n", "<tr><td>Goalkeeper</td></tr>\n</table>\n" + img,
+		}
+	}
+	tests := []struct {
+		name     string
+		page     string   // saved page in testdata
+		id       string   // the player checked
+		edits    []string // old, new pairs applied to the page first
+		wantNats []string
+		wantFlag string
+	}{
+		{
+			name:     "cell 3 alt",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			wantNats: []string{"Spain"},
+			wantFlag: rayaFlagSrc,
+		},
+		{
+			name:     "cell 3 keeps every flag of a dual national",
+			page:     "kader_es_real-madrid",
+			id:       "59016",
+			wantNats: []string{"Austria", "Nigeria"},
+			wantFlag: alabaFlag1,
+		},
+		{
+			name:     "cell 3 title when alt is empty",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    []string{`title="Spain" alt="Spain"`, `title="Spain" alt=""`},
+			wantNats: []string{"Spain"},
+			wantFlag: rayaFlagSrc,
+		},
+		{
+			name:     "cell 3 data-src wins for the flag url",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    []string{`<img src="` + rayaFlagSrc + `" title="Spain"`, `<img src="data:image/gif;base64,R0lGOD" data-src="` + rayaFlagSrc + `" title="Spain"`},
+			wantNats: []string{"Spain"},
+			wantFlag: rayaFlagSrc,
+		},
+		{
+			name:     "cell 3 file names are not nationalities",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    []string{`title="Spain" alt="Spain"`, `title="157.png?lm=1520611569" alt=""`},
+			wantNats: []string{},
+			wantFlag: rayaFlagSrc,
+		},
+		{
+			name:     "cell 3 flag without alt or title",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    []string{`title="Spain" alt="Spain" `, ``},
+			wantNats: []string{},
+			wantFlag: rayaFlagSrc,
+		},
+		{
+			name:     "flag class anywhere in the row",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    moveFlag(`<img src="/images/flag.png" data-src="` + rayaFlagSrc + `" title="Spain" alt="ESP" class="flaggenrahmen" />`),
+			wantNats: []string{"Spain"},
+			wantFlag: rayaFlagSrc,
+		},
+		{
+			name:     "flag src anywhere in the row, alt when there is no title",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    moveFlag(`<img src="` + rayaFlagSrc + `" alt="Spain" />`),
+			wantNats: []string{"Spain"},
+			wantFlag: rayaFlagSrc,
+		},
+		{
+			name:     "raw html regex for lazy flags without a flag class or src",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    moveFlag(`<img src="data:image/gif;base64,R0lGOD" data-src="` + rayaFlagSrc + `" title="Spain" class="lazy" />`),
+			wantNats: []string{"Spain"},
+			wantFlag: "",
+		},
+		{
+			name:     "no flag at all",
+			page:     "kader_com_arsenal",
+			id:       "262749",
+			edits:    []string{rayaNatCell, `<td class="zentriert"></td>`},
+			wantNats: []string{},
+			wantFlag: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := string(readPage(t, tt.page))
+			for i := 0; i+1 < len(tt.edits); i += 2 {
+				if !strings.Contains(page, tt.edits[i]) {
+					t.Fatalf("%s has no %q to edit", tt.page, tt.edits[i])
+				}
+				page = strings.Replace(page, tt.edits[i], tt.edits[i+1], 1)
+			}
+
+			players, err := ParseClubRoster(strings.NewReader(page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The player's own row comes first; the inline table's row
+			// follows as a partial duplicate.
+			i := slices.IndexFunc(players, func(p Player) bool { return p.ID == tt.id })
+			if i < 0 {
+				t.Fatalf("player %s not parsed", tt.id)
+			}
+			p := players[i]
+			if !reflect.DeepEqual(p.Nationalities, tt.wantNats) {
+				t.Errorf("nationalities = %q, want %q", p.Nationalities, tt.wantNats)
+			}
+			if p.FlagURL != tt.wantFlag {
+				t.Errorf("flag url = %q, want %q", p.FlagURL, tt.wantFlag)
+			}
+		})
+	}
+}